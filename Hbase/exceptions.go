@@ -0,0 +1,16 @@
+package Hbase
+
+// The exceptions of the service are errors, so that errors.As finds them in
+// the errors of the hbase package.
+
+func (p *IOError) Error() string {
+	return "IOError: " + p.Message
+}
+
+func (p *IllegalArgument) Error() string {
+	return "IllegalArgument: " + p.Message
+}
+
+func (p *AlreadyExists) Error() string {
+	return "AlreadyExists: " + p.Message
+}
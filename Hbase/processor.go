@@ -0,0 +1,1040 @@
+package Hbase
+
+import (
+	"github.com/J-J-J/hbase/thrift"
+)
+
+// HbaseProcessor serves the Hbase service on top of an IHbase handler, it
+// is the server side counterpart of HbaseClient. Use it with
+// thrift.NewTSimpleServer to host a thrift gateway.
+type HbaseProcessor struct {
+	processorMap map[string]thrift.TProcessorFunction
+	handler      IHbase
+}
+
+// NewHbaseProcessor return a processor dispatching every call to handler.
+// An IOError, IllegalArgument or AlreadyExists returned by the handler is
+// sent to the client as the declared exception, any other error as an
+// INTERNAL_ERROR application exception.
+func NewHbaseProcessor(handler IHbase) *HbaseProcessor {
+	p := &HbaseProcessor{handler: handler, processorMap: make(map[string]thrift.TProcessorFunction)}
+	p.processorMap["enableTable"] = &hbaseProcessorEnableTable{handler: handler}
+	p.processorMap["disableTable"] = &hbaseProcessorDisableTable{handler: handler}
+	p.processorMap["isTableEnabled"] = &hbaseProcessorIsTableEnabled{handler: handler}
+	p.processorMap["compact"] = &hbaseProcessorCompact{handler: handler}
+	p.processorMap["majorCompact"] = &hbaseProcessorMajorCompact{handler: handler}
+	p.processorMap["getTableNames"] = &hbaseProcessorGetTableNames{handler: handler}
+	p.processorMap["getColumnDescriptors"] = &hbaseProcessorGetColumnDescriptors{handler: handler}
+	p.processorMap["getTableRegions"] = &hbaseProcessorGetTableRegions{handler: handler}
+	p.processorMap["createTable"] = &hbaseProcessorCreateTable{handler: handler}
+	p.processorMap["deleteTable"] = &hbaseProcessorDeleteTable{handler: handler}
+	p.processorMap["get"] = &hbaseProcessorGet{handler: handler}
+	p.processorMap["getVer"] = &hbaseProcessorGetVer{handler: handler}
+	p.processorMap["getVerTs"] = &hbaseProcessorGetVerTs{handler: handler}
+	p.processorMap["getRow"] = &hbaseProcessorGetRow{handler: handler}
+	p.processorMap["getRowWithColumns"] = &hbaseProcessorGetRowWithColumns{handler: handler}
+	p.processorMap["getRowTs"] = &hbaseProcessorGetRowTs{handler: handler}
+	p.processorMap["getRowWithColumnsTs"] = &hbaseProcessorGetRowWithColumnsTs{handler: handler}
+	p.processorMap["getRows"] = &hbaseProcessorGetRows{handler: handler}
+	p.processorMap["getRowsWithColumns"] = &hbaseProcessorGetRowsWithColumns{handler: handler}
+	p.processorMap["getRowsTs"] = &hbaseProcessorGetRowsTs{handler: handler}
+	p.processorMap["getRowsWithColumnsTs"] = &hbaseProcessorGetRowsWithColumnsTs{handler: handler}
+	p.processorMap["mutateRow"] = &hbaseProcessorMutateRow{handler: handler}
+	p.processorMap["mutateRowTs"] = &hbaseProcessorMutateRowTs{handler: handler}
+	p.processorMap["mutateRows"] = &hbaseProcessorMutateRows{handler: handler}
+	p.processorMap["mutateRowsTs"] = &hbaseProcessorMutateRowsTs{handler: handler}
+	p.processorMap["atomicIncrement"] = &hbaseProcessorAtomicIncrement{handler: handler}
+	p.processorMap["deleteAll"] = &hbaseProcessorDeleteAll{handler: handler}
+	p.processorMap["deleteAllTs"] = &hbaseProcessorDeleteAllTs{handler: handler}
+	p.processorMap["deleteAllRow"] = &hbaseProcessorDeleteAllRow{handler: handler}
+	p.processorMap["increment"] = &hbaseProcessorIncrement{handler: handler}
+	p.processorMap["incrementRows"] = &hbaseProcessorIncrementRows{handler: handler}
+	p.processorMap["deleteAllRowTs"] = &hbaseProcessorDeleteAllRowTs{handler: handler}
+	p.processorMap["scannerOpenWithScan"] = &hbaseProcessorScannerOpenWithScan{handler: handler}
+	p.processorMap["scannerOpen"] = &hbaseProcessorScannerOpen{handler: handler}
+	p.processorMap["scannerOpenWithStop"] = &hbaseProcessorScannerOpenWithStop{handler: handler}
+	p.processorMap["scannerOpenWithPrefix"] = &hbaseProcessorScannerOpenWithPrefix{handler: handler}
+	p.processorMap["scannerOpenTs"] = &hbaseProcessorScannerOpenTs{handler: handler}
+	p.processorMap["scannerOpenWithStopTs"] = &hbaseProcessorScannerOpenWithStopTs{handler: handler}
+	p.processorMap["scannerGet"] = &hbaseProcessorScannerGet{handler: handler}
+	p.processorMap["scannerGetList"] = &hbaseProcessorScannerGetList{handler: handler}
+	p.processorMap["scannerClose"] = &hbaseProcessorScannerClose{handler: handler}
+	p.processorMap["getRowOrBefore"] = &hbaseProcessorGetRowOrBefore{handler: handler}
+	p.processorMap["getRegionInfo"] = &hbaseProcessorGetRegionInfo{handler: handler}
+	return p
+}
+
+// Handler return the handler calls are dispatched to.
+func (p *HbaseProcessor) Handler() IHbase {
+	return p.handler
+}
+
+// AddToProcessorMap replaces or adds the function processing method key.
+func (p *HbaseProcessor) AddToProcessorMap(key string, processor thrift.TProcessorFunction) {
+	p.processorMap[key] = processor
+}
+
+// GetProcessorFunction return the function processing method key.
+func (p *HbaseProcessor) GetProcessorFunction(key string) (processor thrift.TProcessorFunction, ok bool) {
+	processor, ok = p.processorMap[key]
+	return processor, ok
+}
+
+// ProcessorMap return the functions by method name.
+func (p *HbaseProcessor) ProcessorMap() map[string]thrift.TProcessorFunction {
+	return p.processorMap
+}
+
+// Process reads one call from iprot and writes its reply to oprot. An
+// unknown method is skipped and answered with an UNKNOWN_METHOD exception,
+// the connection staying usable, while arguments that cannot be read leave
+// it out of sync and end it.
+func (p *HbaseProcessor) Process(iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	name, _, seqId, err := iprot.ReadMessageBegin()
+	if err != nil {
+		return false, err
+	}
+	if processor, ok := p.GetProcessorFunction(name); ok {
+		return processor.Process(seqId, iprot, oprot)
+	}
+	iprot.Skip(thrift.STRUCT)
+	iprot.ReadMessageEnd()
+	x := thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "Unknown function "+name)
+	return true, writeException(oprot, name, seqId, x)
+}
+
+// writeException sends x as the reply to call seqId. It return x, or the
+// error that prevented sending it.
+func writeException(oprot thrift.TProtocol, name string, seqId int32, x thrift.TApplicationException) thrift.TException {
+	oprot.WriteMessageBegin(name, thrift.EXCEPTION, seqId)
+	x.Write(oprot)
+	oprot.WriteMessageEnd()
+	if err := oprot.Flush(); err != nil {
+		return err
+	}
+	return x
+}
+
+type resultWriter interface {
+	Write(oprot thrift.TProtocol) thrift.TProtocolException
+}
+
+// writeReply sends result as the reply to call seqId.
+func writeReply(oprot thrift.TProtocol, name string, seqId int32, result resultWriter) (bool, thrift.TException) {
+	if err := oprot.WriteMessageBegin(name, thrift.REPLY, seqId); err != nil {
+		return false, err
+	}
+	if err := result.Write(oprot); err != nil {
+		return false, err
+	}
+	if err := oprot.WriteMessageEnd(); err != nil {
+		return false, err
+	}
+	if err := oprot.Flush(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+type hbaseProcessorEnableTable struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorEnableTable) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewEnableTableArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "enableTable", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewEnableTableResult()
+	var err error
+	if result.Io, err = p.handler.EnableTable(args.TableName); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing enableTable: "+err.Error())
+		return true, writeException(oprot, "enableTable", seqId, x)
+	}
+	return writeReply(oprot, "enableTable", seqId, result)
+}
+
+type hbaseProcessorDisableTable struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorDisableTable) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewDisableTableArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "disableTable", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewDisableTableResult()
+	var err error
+	if result.Io, err = p.handler.DisableTable(args.TableName); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing disableTable: "+err.Error())
+		return true, writeException(oprot, "disableTable", seqId, x)
+	}
+	return writeReply(oprot, "disableTable", seqId, result)
+}
+
+type hbaseProcessorIsTableEnabled struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorIsTableEnabled) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewIsTableEnabledArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "isTableEnabled", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewIsTableEnabledResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.IsTableEnabled(args.TableName); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing isTableEnabled: "+err.Error())
+		return true, writeException(oprot, "isTableEnabled", seqId, x)
+	}
+	return writeReply(oprot, "isTableEnabled", seqId, result)
+}
+
+type hbaseProcessorCompact struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorCompact) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewCompactArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "compact", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewCompactResult()
+	var err error
+	if result.Io, err = p.handler.Compact(args.TableNameOrRegionName); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing compact: "+err.Error())
+		return true, writeException(oprot, "compact", seqId, x)
+	}
+	return writeReply(oprot, "compact", seqId, result)
+}
+
+type hbaseProcessorMajorCompact struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorMajorCompact) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewMajorCompactArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "majorCompact", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewMajorCompactResult()
+	var err error
+	if result.Io, err = p.handler.MajorCompact(args.TableNameOrRegionName); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing majorCompact: "+err.Error())
+		return true, writeException(oprot, "majorCompact", seqId, x)
+	}
+	return writeReply(oprot, "majorCompact", seqId, result)
+}
+
+type hbaseProcessorGetTableNames struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetTableNames) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetTableNamesArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getTableNames", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetTableNamesResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetTableNames(); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getTableNames: "+err.Error())
+		return true, writeException(oprot, "getTableNames", seqId, x)
+	}
+	return writeReply(oprot, "getTableNames", seqId, result)
+}
+
+type hbaseProcessorGetColumnDescriptors struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetColumnDescriptors) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetColumnDescriptorsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getColumnDescriptors", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetColumnDescriptorsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetColumnDescriptors(args.TableName); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getColumnDescriptors: "+err.Error())
+		return true, writeException(oprot, "getColumnDescriptors", seqId, x)
+	}
+	return writeReply(oprot, "getColumnDescriptors", seqId, result)
+}
+
+type hbaseProcessorGetTableRegions struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetTableRegions) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetTableRegionsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getTableRegions", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetTableRegionsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetTableRegions(args.TableName); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getTableRegions: "+err.Error())
+		return true, writeException(oprot, "getTableRegions", seqId, x)
+	}
+	return writeReply(oprot, "getTableRegions", seqId, result)
+}
+
+type hbaseProcessorCreateTable struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorCreateTable) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewCreateTableArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "createTable", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewCreateTableResult()
+	var err error
+	if result.Io, result.Ia, result.Exist, err = p.handler.CreateTable(args.TableName, args.ColumnFamilies); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing createTable: "+err.Error())
+		return true, writeException(oprot, "createTable", seqId, x)
+	}
+	return writeReply(oprot, "createTable", seqId, result)
+}
+
+type hbaseProcessorDeleteTable struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorDeleteTable) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewDeleteTableArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "deleteTable", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewDeleteTableResult()
+	var err error
+	if result.Io, err = p.handler.DeleteTable(args.TableName); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing deleteTable: "+err.Error())
+		return true, writeException(oprot, "deleteTable", seqId, x)
+	}
+	return writeReply(oprot, "deleteTable", seqId, result)
+}
+
+type hbaseProcessorGet struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGet) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "get", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.Get(args.TableName, args.Row, args.Column, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing get: "+err.Error())
+		return true, writeException(oprot, "get", seqId, x)
+	}
+	return writeReply(oprot, "get", seqId, result)
+}
+
+type hbaseProcessorGetVer struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetVer) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetVerArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getVer", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetVerResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetVer(args.TableName, args.Row, args.Column, args.NumVersions, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getVer: "+err.Error())
+		return true, writeException(oprot, "getVer", seqId, x)
+	}
+	return writeReply(oprot, "getVer", seqId, result)
+}
+
+type hbaseProcessorGetVerTs struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetVerTs) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetVerTsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getVerTs", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetVerTsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetVerTs(args.TableName, args.Row, args.Column, args.Timestamp, args.NumVersions, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getVerTs: "+err.Error())
+		return true, writeException(oprot, "getVerTs", seqId, x)
+	}
+	return writeReply(oprot, "getVerTs", seqId, result)
+}
+
+type hbaseProcessorGetRow struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetRow) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetRowArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getRow", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetRowResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetRow(args.TableName, args.Row, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getRow: "+err.Error())
+		return true, writeException(oprot, "getRow", seqId, x)
+	}
+	return writeReply(oprot, "getRow", seqId, result)
+}
+
+type hbaseProcessorGetRowWithColumns struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetRowWithColumns) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetRowWithColumnsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getRowWithColumns", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetRowWithColumnsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetRowWithColumns(args.TableName, args.Row, args.Columns, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getRowWithColumns: "+err.Error())
+		return true, writeException(oprot, "getRowWithColumns", seqId, x)
+	}
+	return writeReply(oprot, "getRowWithColumns", seqId, result)
+}
+
+type hbaseProcessorGetRowTs struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetRowTs) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetRowTsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getRowTs", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetRowTsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetRowTs(args.TableName, args.Row, args.Timestamp, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getRowTs: "+err.Error())
+		return true, writeException(oprot, "getRowTs", seqId, x)
+	}
+	return writeReply(oprot, "getRowTs", seqId, result)
+}
+
+type hbaseProcessorGetRowWithColumnsTs struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetRowWithColumnsTs) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetRowWithColumnsTsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getRowWithColumnsTs", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetRowWithColumnsTsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetRowWithColumnsTs(args.TableName, args.Row, args.Columns, args.Timestamp, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getRowWithColumnsTs: "+err.Error())
+		return true, writeException(oprot, "getRowWithColumnsTs", seqId, x)
+	}
+	return writeReply(oprot, "getRowWithColumnsTs", seqId, result)
+}
+
+type hbaseProcessorGetRows struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetRows) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetRowsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getRows", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetRowsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetRows(args.TableName, args.Rows, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getRows: "+err.Error())
+		return true, writeException(oprot, "getRows", seqId, x)
+	}
+	return writeReply(oprot, "getRows", seqId, result)
+}
+
+type hbaseProcessorGetRowsWithColumns struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetRowsWithColumns) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetRowsWithColumnsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getRowsWithColumns", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetRowsWithColumnsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetRowsWithColumns(args.TableName, args.Rows, args.Columns, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getRowsWithColumns: "+err.Error())
+		return true, writeException(oprot, "getRowsWithColumns", seqId, x)
+	}
+	return writeReply(oprot, "getRowsWithColumns", seqId, result)
+}
+
+type hbaseProcessorGetRowsTs struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetRowsTs) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetRowsTsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getRowsTs", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetRowsTsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetRowsTs(args.TableName, args.Rows, args.Timestamp, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getRowsTs: "+err.Error())
+		return true, writeException(oprot, "getRowsTs", seqId, x)
+	}
+	return writeReply(oprot, "getRowsTs", seqId, result)
+}
+
+type hbaseProcessorGetRowsWithColumnsTs struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetRowsWithColumnsTs) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetRowsWithColumnsTsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getRowsWithColumnsTs", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetRowsWithColumnsTsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetRowsWithColumnsTs(args.TableName, args.Rows, args.Columns, args.Timestamp, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getRowsWithColumnsTs: "+err.Error())
+		return true, writeException(oprot, "getRowsWithColumnsTs", seqId, x)
+	}
+	return writeReply(oprot, "getRowsWithColumnsTs", seqId, result)
+}
+
+type hbaseProcessorMutateRow struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorMutateRow) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewMutateRowArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "mutateRow", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewMutateRowResult()
+	var err error
+	if result.Io, result.Ia, err = p.handler.MutateRow(args.TableName, args.Row, args.Mutations, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing mutateRow: "+err.Error())
+		return true, writeException(oprot, "mutateRow", seqId, x)
+	}
+	return writeReply(oprot, "mutateRow", seqId, result)
+}
+
+type hbaseProcessorMutateRowTs struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorMutateRowTs) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewMutateRowTsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "mutateRowTs", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewMutateRowTsResult()
+	var err error
+	if result.Io, result.Ia, err = p.handler.MutateRowTs(args.TableName, args.Row, args.Mutations, args.Timestamp, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing mutateRowTs: "+err.Error())
+		return true, writeException(oprot, "mutateRowTs", seqId, x)
+	}
+	return writeReply(oprot, "mutateRowTs", seqId, result)
+}
+
+type hbaseProcessorMutateRows struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorMutateRows) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewMutateRowsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "mutateRows", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewMutateRowsResult()
+	var err error
+	if result.Io, result.Ia, err = p.handler.MutateRows(args.TableName, args.RowBatches, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing mutateRows: "+err.Error())
+		return true, writeException(oprot, "mutateRows", seqId, x)
+	}
+	return writeReply(oprot, "mutateRows", seqId, result)
+}
+
+type hbaseProcessorMutateRowsTs struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorMutateRowsTs) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewMutateRowsTsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "mutateRowsTs", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewMutateRowsTsResult()
+	var err error
+	if result.Io, result.Ia, err = p.handler.MutateRowsTs(args.TableName, args.RowBatches, args.Timestamp, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing mutateRowsTs: "+err.Error())
+		return true, writeException(oprot, "mutateRowsTs", seqId, x)
+	}
+	return writeReply(oprot, "mutateRowsTs", seqId, result)
+}
+
+type hbaseProcessorAtomicIncrement struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorAtomicIncrement) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewAtomicIncrementArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "atomicIncrement", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewAtomicIncrementResult()
+	var err error
+	if result.Success, result.Io, result.Ia, err = p.handler.AtomicIncrement(args.TableName, args.Row, args.Column, args.Value); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing atomicIncrement: "+err.Error())
+		return true, writeException(oprot, "atomicIncrement", seqId, x)
+	}
+	return writeReply(oprot, "atomicIncrement", seqId, result)
+}
+
+type hbaseProcessorDeleteAll struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorDeleteAll) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewDeleteAllArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "deleteAll", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewDeleteAllResult()
+	var err error
+	if result.Io, err = p.handler.DeleteAll(args.TableName, args.Row, args.Column, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing deleteAll: "+err.Error())
+		return true, writeException(oprot, "deleteAll", seqId, x)
+	}
+	return writeReply(oprot, "deleteAll", seqId, result)
+}
+
+type hbaseProcessorDeleteAllTs struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorDeleteAllTs) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewDeleteAllTsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "deleteAllTs", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewDeleteAllTsResult()
+	var err error
+	if result.Io, err = p.handler.DeleteAllTs(args.TableName, args.Row, args.Column, args.Timestamp, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing deleteAllTs: "+err.Error())
+		return true, writeException(oprot, "deleteAllTs", seqId, x)
+	}
+	return writeReply(oprot, "deleteAllTs", seqId, result)
+}
+
+type hbaseProcessorDeleteAllRow struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorDeleteAllRow) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewDeleteAllRowArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "deleteAllRow", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewDeleteAllRowResult()
+	var err error
+	if result.Io, err = p.handler.DeleteAllRow(args.TableName, args.Row, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing deleteAllRow: "+err.Error())
+		return true, writeException(oprot, "deleteAllRow", seqId, x)
+	}
+	return writeReply(oprot, "deleteAllRow", seqId, result)
+}
+
+type hbaseProcessorIncrement struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorIncrement) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewIncrementArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "increment", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewIncrementResult()
+	var err error
+	if result.Io, err = p.handler.Increment(args.Increment); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing increment: "+err.Error())
+		return true, writeException(oprot, "increment", seqId, x)
+	}
+	return writeReply(oprot, "increment", seqId, result)
+}
+
+type hbaseProcessorIncrementRows struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorIncrementRows) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewIncrementRowsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "incrementRows", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewIncrementRowsResult()
+	var err error
+	if result.Io, err = p.handler.IncrementRows(args.Increments); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing incrementRows: "+err.Error())
+		return true, writeException(oprot, "incrementRows", seqId, x)
+	}
+	return writeReply(oprot, "incrementRows", seqId, result)
+}
+
+type hbaseProcessorDeleteAllRowTs struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorDeleteAllRowTs) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewDeleteAllRowTsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "deleteAllRowTs", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewDeleteAllRowTsResult()
+	var err error
+	if result.Io, err = p.handler.DeleteAllRowTs(args.TableName, args.Row, args.Timestamp, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing deleteAllRowTs: "+err.Error())
+		return true, writeException(oprot, "deleteAllRowTs", seqId, x)
+	}
+	return writeReply(oprot, "deleteAllRowTs", seqId, result)
+}
+
+type hbaseProcessorScannerOpenWithScan struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorScannerOpenWithScan) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewScannerOpenWithScanArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "scannerOpenWithScan", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewScannerOpenWithScanResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.ScannerOpenWithScan(args.TableName, args.Scan, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing scannerOpenWithScan: "+err.Error())
+		return true, writeException(oprot, "scannerOpenWithScan", seqId, x)
+	}
+	return writeReply(oprot, "scannerOpenWithScan", seqId, result)
+}
+
+type hbaseProcessorScannerOpen struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorScannerOpen) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewScannerOpenArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "scannerOpen", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewScannerOpenResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.ScannerOpen(args.TableName, args.StartRow, args.Columns, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing scannerOpen: "+err.Error())
+		return true, writeException(oprot, "scannerOpen", seqId, x)
+	}
+	return writeReply(oprot, "scannerOpen", seqId, result)
+}
+
+type hbaseProcessorScannerOpenWithStop struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorScannerOpenWithStop) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewScannerOpenWithStopArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "scannerOpenWithStop", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewScannerOpenWithStopResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.ScannerOpenWithStop(args.TableName, args.StartRow, args.StopRow, args.Columns, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing scannerOpenWithStop: "+err.Error())
+		return true, writeException(oprot, "scannerOpenWithStop", seqId, x)
+	}
+	return writeReply(oprot, "scannerOpenWithStop", seqId, result)
+}
+
+type hbaseProcessorScannerOpenWithPrefix struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorScannerOpenWithPrefix) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewScannerOpenWithPrefixArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "scannerOpenWithPrefix", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewScannerOpenWithPrefixResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.ScannerOpenWithPrefix(args.TableName, args.StartAndPrefix, args.Columns, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing scannerOpenWithPrefix: "+err.Error())
+		return true, writeException(oprot, "scannerOpenWithPrefix", seqId, x)
+	}
+	return writeReply(oprot, "scannerOpenWithPrefix", seqId, result)
+}
+
+type hbaseProcessorScannerOpenTs struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorScannerOpenTs) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewScannerOpenTsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "scannerOpenTs", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewScannerOpenTsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.ScannerOpenTs(args.TableName, args.StartRow, args.Columns, args.Timestamp, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing scannerOpenTs: "+err.Error())
+		return true, writeException(oprot, "scannerOpenTs", seqId, x)
+	}
+	return writeReply(oprot, "scannerOpenTs", seqId, result)
+}
+
+type hbaseProcessorScannerOpenWithStopTs struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorScannerOpenWithStopTs) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewScannerOpenWithStopTsArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "scannerOpenWithStopTs", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewScannerOpenWithStopTsResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.ScannerOpenWithStopTs(args.TableName, args.StartRow, args.StopRow, args.Columns, args.Timestamp, args.Attributes); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing scannerOpenWithStopTs: "+err.Error())
+		return true, writeException(oprot, "scannerOpenWithStopTs", seqId, x)
+	}
+	return writeReply(oprot, "scannerOpenWithStopTs", seqId, result)
+}
+
+type hbaseProcessorScannerGet struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorScannerGet) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewScannerGetArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "scannerGet", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewScannerGetResult()
+	var err error
+	if result.Success, result.Io, result.Ia, err = p.handler.ScannerGet(args.Id); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing scannerGet: "+err.Error())
+		return true, writeException(oprot, "scannerGet", seqId, x)
+	}
+	return writeReply(oprot, "scannerGet", seqId, result)
+}
+
+type hbaseProcessorScannerGetList struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorScannerGetList) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewScannerGetListArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "scannerGetList", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewScannerGetListResult()
+	var err error
+	if result.Success, result.Io, result.Ia, err = p.handler.ScannerGetList(args.Id, args.NbRows); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing scannerGetList: "+err.Error())
+		return true, writeException(oprot, "scannerGetList", seqId, x)
+	}
+	return writeReply(oprot, "scannerGetList", seqId, result)
+}
+
+type hbaseProcessorScannerClose struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorScannerClose) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewScannerCloseArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "scannerClose", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewScannerCloseResult()
+	var err error
+	if result.Io, result.Ia, err = p.handler.ScannerClose(args.Id); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing scannerClose: "+err.Error())
+		return true, writeException(oprot, "scannerClose", seqId, x)
+	}
+	return writeReply(oprot, "scannerClose", seqId, result)
+}
+
+type hbaseProcessorGetRowOrBefore struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetRowOrBefore) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetRowOrBeforeArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getRowOrBefore", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetRowOrBeforeResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetRowOrBefore(args.TableName, args.Row, args.Family); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getRowOrBefore: "+err.Error())
+		return true, writeException(oprot, "getRowOrBefore", seqId, x)
+	}
+	return writeReply(oprot, "getRowOrBefore", seqId, result)
+}
+
+type hbaseProcessorGetRegionInfo struct {
+	handler IHbase
+}
+
+func (p *hbaseProcessorGetRegionInfo) Process(seqId int32, iprot, oprot thrift.TProtocol) (bool, thrift.TException) {
+	args := NewGetRegionInfoArgs()
+	if err := args.Read(iprot); err != nil {
+		iprot.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		return false, writeException(oprot, "getRegionInfo", seqId, x)
+	}
+	iprot.ReadMessageEnd()
+	result := NewGetRegionInfoResult()
+	var err error
+	if result.Success, result.Io, err = p.handler.GetRegionInfo(args.Row); err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing getRegionInfo: "+err.Error())
+		return true, writeException(oprot, "getRegionInfo", seqId, x)
+	}
+	return writeReply(oprot, "getRegionInfo", seqId, result)
+}
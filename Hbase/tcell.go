@@ -0,0 +1,55 @@
+package Hbase
+
+import (
+	"github.com/J-J-J/hbase/codec"
+)
+
+// Accessors decoding the value of a cell written by the Java Bytes class, see
+// package codec. A nil cell has an empty value.
+
+func (p *TCell) value() []byte {
+	if p == nil {
+		return nil
+	}
+	return p.Value
+}
+
+// Int64 decodes a long value, or an AtomicIncrement counter.
+func (p *TCell) Int64() (int64, error) {
+	return codec.DecodeInt64(p.value())
+}
+
+// Int32 decodes an int value.
+func (p *TCell) Int32() (int32, error) {
+	return codec.DecodeInt32(p.value())
+}
+
+// Int16 decodes a short value.
+func (p *TCell) Int16() (int16, error) {
+	return codec.DecodeInt16(p.value())
+}
+
+// Float64 decodes a double value.
+func (p *TCell) Float64() (float64, error) {
+	return codec.DecodeFloat64(p.value())
+}
+
+// Float32 decodes a float value.
+func (p *TCell) Float32() (float32, error) {
+	return codec.DecodeFloat32(p.value())
+}
+
+// Bool decodes a boolean value.
+func (p *TCell) Bool() (bool, error) {
+	return codec.DecodeBool(p.value())
+}
+
+// Str decodes a String value.
+func (p *TCell) Str() (string, error) {
+	return codec.DecodeString(p.value())
+}
+
+// BigDecimal decodes a BigDecimal value.
+func (p *TCell) BigDecimal() (codec.BigDecimal, error) {
+	return codec.DecodeBigDecimal(p.value())
+}
@@ -0,0 +1,217 @@
+package hbase
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BalancePolicy picks the gateway a new connection goes to.
+type BalancePolicy int
+
+// balance policies
+const (
+	RoundRobin        BalancePolicy = iota // cycle through the gateways
+	LeastOutstanding                       // fewest calls in flight
+	PowerOfTwoChoices                      // fewer calls in flight of two random gateways
+)
+
+// BalancerConfig configures a Balancer.
+type BalancerConfig struct {
+	Policy BalancePolicy
+	// Dial creates an unopened client for addr, nil uses
+	// NewTCPClient(addr, false).
+	Dial func(addr string) (*HClient, error)
+	// MaxFailures is the number of consecutive transport errors that eject
+	// a gateway, zero uses 3.
+	MaxFailures int
+	// EjectDuration is how long an ejected gateway gets no new connections.
+	// Afterwards it is probed again, one more failure ejects it at once and
+	// one success restores it. Zero uses 30 seconds.
+	EjectDuration time.Duration
+}
+
+type gateway struct {
+	addr        string
+	outstanding int64 // calls in flight, atomic
+
+	mu           sync.Mutex
+	failures     int
+	ejectedUntil time.Time
+}
+
+func (gw *gateway) acquire() {
+	atomic.AddInt64(&gw.outstanding, 1)
+}
+
+func (gw *gateway) release() {
+	atomic.AddInt64(&gw.outstanding, -1)
+}
+
+func (gw *gateway) load() int64 {
+	return atomic.LoadInt64(&gw.outstanding)
+}
+
+func (gw *gateway) ejected(now time.Time) bool {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	return now.Before(gw.ejectedUntil)
+}
+
+// Balancer spreads connections over several thrift gateways and stops using
+// a gateway that keeps failing in the transport. Every connection stays on
+// the gateway it was dialed to, including when it reconnects, so a scanner
+// opened on a client must be read and closed on the same client: scanner
+// ids are only known to the gateway that opened them.
+type Balancer struct {
+	cfg      BalancerConfig
+	gateways []*gateway
+	next     uint32 // round robin cursor, atomic
+
+	randMu sync.Mutex
+	rand   *rand.Rand
+}
+
+// NewBalancer return a balancer over the gateways at addrs.
+func NewBalancer(addrs []string, cfg BalancerConfig) (*Balancer, error) {
+	if len(addrs) == 0 {
+		return nil, errors.New("hbase: balancer needs at least one address")
+	}
+	if cfg.Dial == nil {
+		cfg.Dial = func(addr string) (*HClient, error) {
+			return NewTCPClient(addr, false)
+		}
+	}
+	if cfg.MaxFailures <= 0 {
+		cfg.MaxFailures = 3
+	}
+	if cfg.EjectDuration <= 0 {
+		cfg.EjectDuration = 30 * time.Second
+	}
+	b := &Balancer{
+		cfg:  cfg,
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, addr := range addrs {
+		b.gateways = append(b.gateways, &gateway{addr: addr})
+	}
+	return b, nil
+}
+
+// NewBalancedPool return a pool whose connections are spread over addrs.
+func NewBalancedPool(addrs []string, bcfg BalancerConfig, pcfg PoolConfig) (*HClientPool, error) {
+	b, err := NewBalancer(addrs, bcfg)
+	if err != nil {
+		return nil, err
+	}
+	pcfg.Dial = b.Dial
+	return NewHClientPool(pcfg)
+}
+
+// Dial opens a client to the gateway chosen by the policy, failing over to
+// the other gateways when it cannot connect.
+func (b *Balancer) Dial() (*HClient, error) {
+	tried := make(map[*gateway]bool, len(b.gateways))
+	var lastErr error
+	for len(tried) < len(b.gateways) {
+		gw := b.pick(tried)
+		tried[gw] = true
+
+		client, err := b.cfg.Dial(gw.addr)
+		if err == nil {
+			err = client.Open()
+		}
+		if err != nil {
+			b.report(gw, true)
+			lastErr = err
+			continue
+		}
+		client.gw = gw
+		client.balancer = b
+		return client, nil
+	}
+	return nil, lastErr
+}
+
+// Ejected return the addresses currently getting no new connections.
+func (b *Balancer) Ejected() []string {
+	now := time.Now()
+	var addrs []string
+	for _, gw := range b.gateways {
+		if gw.ejected(now) {
+			addrs = append(addrs, gw.addr)
+		}
+	}
+	return addrs
+}
+
+// report records the outcome of a connect or call on gw.
+func (b *Balancer) report(gw *gateway, failed bool) {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	if !failed {
+		gw.failures = 0
+		gw.ejectedUntil = time.Time{}
+		return
+	}
+	gw.failures++
+	if gw.failures >= b.cfg.MaxFailures {
+		gw.ejectedUntil = time.Now().Add(b.cfg.EjectDuration)
+	}
+}
+
+// pick chooses a gateway not in tried. When every candidate is ejected the
+// one coming back first is used.
+func (b *Balancer) pick(tried map[*gateway]bool) *gateway {
+	now := time.Now()
+	var healthy []*gateway
+	var soonest *gateway
+	var soonestAt time.Time
+	for _, gw := range b.gateways {
+		if tried[gw] {
+			continue
+		}
+		gw.mu.Lock()
+		until := gw.ejectedUntil
+		gw.mu.Unlock()
+		if !now.Before(until) {
+			healthy = append(healthy, gw)
+		} else if soonest == nil || until.Before(soonestAt) {
+			soonest, soonestAt = gw, until
+		}
+	}
+	if len(healthy) == 0 {
+		return soonest
+	}
+
+	start := int(atomic.AddUint32(&b.next, 1)-1) % len(healthy)
+	switch b.cfg.Policy {
+	case LeastOutstanding:
+		best := healthy[start]
+		for i := 1; i < len(healthy); i++ {
+			gw := healthy[(start+i)%len(healthy)]
+			if gw.load() < best.load() {
+				best = gw
+			}
+		}
+		return best
+	case PowerOfTwoChoices:
+		if len(healthy) == 1 {
+			return healthy[0]
+		}
+		b.randMu.Lock()
+		i := b.rand.Intn(len(healthy))
+		j := b.rand.Intn(len(healthy) - 1)
+		b.randMu.Unlock()
+		if j >= i {
+			j++
+		}
+		if healthy[j].load() < healthy[i].load() {
+			return healthy[j]
+		}
+		return healthy[i]
+	}
+	return healthy[start]
+}
@@ -0,0 +1,516 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/J-J-J/hbase"
+	"github.com/J-J-J/hbase/Hbase"
+)
+
+// errExit is returned by the exit command.
+var errExit = errors.New("exit")
+
+// shell runs commands against a client.
+type shell struct {
+	client *hbase.HClient
+	out    *printer
+	hist   *history
+}
+
+// command is a shell command.
+type command struct {
+	name  string
+	usage string
+	help  string
+	// min and max bound the number of arguments, max < 0 for no bound
+	min, max int
+	run      func(s *shell, ctx context.Context, args []string) error
+}
+
+var commands []*command
+
+func init() {
+	// assigned in init, the help command refers to commands
+	commands = []*command{
+		{"list", "list [regexp]", "list the tables, those matching regexp if given", 0, 1, (*shell).list},
+		{"describe", "describe <table>", "show the state and column families of a table", 1, 1, (*shell).describe},
+		{"get", "get <table> <row> [column ...] [versions=N]", "read a row, or some of its columns; versions needs family:qualifier columns", 2, -1, (*shell).get},
+		{"put", "put <table> <row> <column> <value> [ts=N]", "write a cell, the value being encoded per the column type if declared", 4, 5, (*shell).put},
+		{"scan", "scan <table> [start=R] [stop=R] [prefix=P] [columns=C,...] [filter=F] [limit=N]", "read a range of rows; filter is a filter language string", 1, -1, (*shell).scan},
+		{"delete", "delete <table> <row> [column] [ts=N]", "delete a row, or a column of a row, at or before ts", 2, 4, (*shell).delete},
+		{"incr", "incr <table> <row> <column> [amount]", "atomically add amount, 1 by default, to a long column and show the result", 3, 4, (*shell).incr},
+		{"regions", "regions <table>", "list the regions of a table", 1, 1, (*shell).regions},
+		{"enable", "enable <table>", "enable a table", 1, 1, (*shell).enable},
+		{"disable", "disable <table>", "disable a table", 1, 1, (*shell).disable},
+		{"compact", "compact <table|region>", "request a compaction", 1, 1, (*shell).compact},
+		{"major_compact", "major_compact <table|region>", "request a major compaction", 1, 1, (*shell).majorCompact},
+		{"export", "export <table> <file> [format=jsonl|binary] [start=R] [stop=R] [prefix=P] [columns=C,...] [versions=N]", "write the cells of a table, every version, to a file; an interrupted export continues when run again", 2, -1, (*shell).export},
+		{"import", "import <table> <file> [start=R] [stop=R] [prefix=P] [columns=C,...]", "write the cells of an exported file to a table, with their timestamps; an interrupted import continues when run again", 2, -1, (*shell).importFile},
+		{"format", "format [string|hex|typed]", "show or set how keys and values are printed", 0, 1, (*shell).format},
+		{"json", "json [on|off]", "show or set JSON output, one document per line", 0, 1, (*shell).json},
+		{"type", "type [column|family [type|none]]", "show or declare the type of the values of a column or family: long, int, short, double, float, boolean, string, bigdecimal or binary", 0, 2, (*shell).setType},
+		{"history", "history", "show the history", 0, 0, (*shell).showHistory},
+		{"help", "help [command]", "show the commands, or the help of one", 0, 1, (*shell).help},
+		{"exit", "exit", "leave the shell, also quit or Ctrl-D", 0, 0, (*shell).exit},
+	}
+}
+
+func lookup(name string) *command {
+	if name == "quit" {
+		name = "exit"
+	}
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// usageError is a command called with invalid arguments.
+type usageError struct {
+	cmd *command
+	msg string
+}
+
+func (e *usageError) Error() string {
+	if e.msg != "" {
+		return e.msg + "\nusage: " + e.cmd.usage
+	}
+	return "usage: " + e.cmd.usage
+}
+
+// runLine runs the command of a line, a blank line or a comment being
+// ignored.
+func (s *shell) runLine(ctx context.Context, line string) error {
+	args, err := splitLine(line)
+	if err != nil || len(args) == 0 {
+		return err
+	}
+	return s.runArgs(ctx, args)
+}
+
+// runArgs runs the command args[0] with the arguments args[1:].
+func (s *shell) runArgs(ctx context.Context, args []string) error {
+	c := lookup(args[0])
+	if c == nil {
+		return fmt.Errorf("unknown command %q, see help", args[0])
+	}
+	if n := len(args) - 1; n < c.min || (c.max >= 0 && n > c.max) {
+		return &usageError{cmd: c}
+	}
+	return c.run(s, ctx, args[1:])
+}
+
+// splitLine splits a line into arguments separated by spaces. Quotes group
+// spaces into an argument: '...' is literal, "..." and unquoted text
+// decode \xNN escapes as Bytes.toBytesBinary, and \" in "...". A # starting
+// an argument comments out the rest of the line.
+func splitLine(line string) ([]string, error) {
+	var args []string
+	var arg []byte
+	inArg := false
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == ' ' || c == '\t':
+			if inArg {
+				args, arg, inArg = append(args, string(arg)), nil, false
+			}
+			i++
+		case c == '#' && !inArg:
+			return args, nil
+		case c == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated ' quote")
+			}
+			arg, inArg = append(arg, line[i+1:i+1+end]...), true
+			i += end + 2
+		case c == '"':
+			var quoted strings.Builder
+			j := i + 1
+			for ; j < len(line) && line[j] != '"'; j++ {
+				if line[j] == '\\' && j+1 < len(line) && line[j+1] == '"' {
+					j++
+				}
+				quoted.WriteByte(line[j])
+			}
+			if j == len(line) {
+				return nil, errors.New(`unterminated " quote`)
+			}
+			arg, inArg = append(arg, toBytesBinary(quoted.String())...), true
+			i = j + 1
+		default:
+			j := i
+			for j < len(line) && strings.IndexByte(" \t'\"", line[j]) < 0 {
+				j++
+			}
+			arg, inArg = append(arg, toBytesBinary(line[i:j])...), true
+			i = j
+		}
+	}
+	if inArg {
+		args = append(args, string(arg))
+	}
+	return args, nil
+}
+
+// options splits the key=value arguments whose key is in known from the
+// other arguments.
+func options(args []string, known ...string) (opts map[string]string, rest []string) {
+	opts = map[string]string{}
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if ok && contains(known, key) {
+			opts[key] = value
+		} else {
+			rest = append(rest, arg)
+		}
+	}
+	return
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// intOption parses the option key of opts, def when it is missing.
+func intOption(opts map[string]string, key string, def int64) (int64, error) {
+	v, ok := opts[key]
+	if !ok {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", key, v)
+	}
+	return n, nil
+}
+
+func (s *shell) list(ctx context.Context, args []string) error {
+	var re *regexp.Regexp
+	if len(args) == 1 {
+		var err error
+		if re, err = regexp.Compile(args[0]); err != nil {
+			return err
+		}
+	}
+	names, err := s.client.GetTableNamesContext(ctx)
+	if err != nil {
+		return err
+	}
+	tables := []string{}
+	for _, name := range names {
+		if re == nil || re.MatchString(name) {
+			tables = append(tables, name)
+		}
+	}
+	sort.Strings(tables)
+	if s.out.json {
+		return s.out.encode(tables)
+	}
+	for _, name := range tables {
+		fmt.Fprintln(s.out.w, name)
+	}
+	fmt.Fprintf(s.out.w, "%d table(s)\n", len(tables))
+	return nil
+}
+
+func (s *shell) describe(ctx context.Context, args []string) error {
+	columns, err := s.client.GetColumnDescriptorsContext(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	enabled, err := s.client.IsTableEnabledContext(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return s.out.describe(args[0], enabled, columns)
+}
+
+func (s *shell) get(ctx context.Context, args []string) error {
+	opts, rest := options(args[2:], "versions")
+	table, row, columns := args[0], []byte(args[1]), rest
+	versions, err := intOption(opts, "versions", 0)
+	if err != nil {
+		return err
+	}
+	var cells []cell
+	if versions > 0 {
+		if len(columns) == 0 {
+			return &usageError{cmd: lookup("get"), msg: "versions needs columns"}
+		}
+		for _, column := range columns {
+			if !strings.Contains(column, ":") {
+				return &usageError{cmd: lookup("get"), msg: "versions needs family:qualifier columns"}
+			}
+			found, err := s.client.GetVerContext(ctx, table, row, column, int32(versions), nil)
+			if err != nil {
+				return err
+			}
+			for _, c := range found {
+				cells = append(cells, cell{column, c})
+			}
+		}
+	} else {
+		var rows []*Hbase.TRowResult
+		if len(columns) == 0 {
+			rows, err = s.client.GetRowContext(ctx, table, row, nil)
+		} else {
+			rows, err = s.client.GetRowWithColumnsContext(ctx, table, row, columns, nil)
+		}
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			cells = append(cells, sortedCells(r)...)
+		}
+	}
+	if len(cells) == 0 {
+		s.out.count(0)
+		return nil
+	}
+	if err := s.out.row(row, cells); err != nil {
+		return err
+	}
+	s.out.count(1)
+	return nil
+}
+
+func (s *shell) put(ctx context.Context, args []string) error {
+	opts, rest := options(args[3:], "ts")
+	if len(rest) != 1 {
+		return &usageError{cmd: lookup("put")}
+	}
+	table, row, column := args[0], []byte(args[1]), args[2]
+	value, err := encodeValue(s.out.typeOf(column), rest[0])
+	if err != nil {
+		return err
+	}
+	mutations := []*Hbase.Mutation{hbase.NewMutation(column, value)}
+	if _, ok := opts["ts"]; !ok {
+		return s.client.MutateRowContext(ctx, table, row, mutations, nil)
+	}
+	ts, err := intOption(opts, "ts", 0)
+	if err != nil {
+		return err
+	}
+	return s.client.MutateRowTsContext(ctx, table, row, mutations, ts, nil)
+}
+
+func (s *shell) scan(ctx context.Context, args []string) error {
+	opts, rest := options(args[1:], "start", "stop", "prefix", "columns", "filter", "limit")
+	if len(rest) > 0 {
+		return &usageError{cmd: lookup("scan"), msg: "unknown option " + strconv.Quote(rest[0])}
+	}
+	limit, err := intOption(opts, "limit", 0)
+	if err != nil {
+		return err
+	}
+	scan := &hbase.TScan{FilterString: opts["filter"]}
+	if scan.StartRow, scan.StopRow, err = keyRange("scan", opts); err != nil {
+		return err
+	}
+	scan.Columns = columnList(opts)
+	if limit > 0 && limit < hbase.DefaultScanBatch {
+		scan.Caching = int32(limit)
+	}
+	sc, err := s.client.ScanContext(ctx, args[0], scan, nil)
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+	n := int64(0)
+	for (limit <= 0 || n < limit) && sc.Next() {
+		row := sc.Row()
+		if err := s.out.row(row.Row, sortedCells(row)); err != nil {
+			return err
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	s.out.count(int(n))
+	return nil
+}
+
+func (s *shell) delete(ctx context.Context, args []string) error {
+	opts, rest := options(args[2:], "ts")
+	if len(rest) > 1 {
+		return &usageError{cmd: lookup("delete")}
+	}
+	table, row := args[0], []byte(args[1])
+	_, hasTs := opts["ts"]
+	ts, err := intOption(opts, "ts", 0)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(rest) == 0 && hasTs:
+		return s.client.DeleteAllRowTsContext(ctx, table, row, ts, nil)
+	case len(rest) == 0:
+		return s.client.DeleteAllRowContext(ctx, table, row, nil)
+	case hasTs:
+		return s.client.DeleteAllTsContext(ctx, table, row, rest[0], ts, nil)
+	}
+	return s.client.DeleteAllContext(ctx, table, row, rest[0], nil)
+}
+
+func (s *shell) incr(ctx context.Context, args []string) error {
+	amount := int64(1)
+	if len(args) == 4 {
+		var err error
+		if amount, err = strconv.ParseInt(args[3], 10, 64); err != nil {
+			return fmt.Errorf("invalid amount %q", args[3])
+		}
+	}
+	v, err := s.client.AtomicIncrementContext(ctx, args[0], []byte(args[1]), args[2], amount)
+	if err != nil {
+		return err
+	}
+	if s.out.json {
+		return s.out.encode(struct {
+			Value int64 `json:"value"`
+		}{v})
+	}
+	fmt.Fprintf(s.out.w, "COUNTER VALUE = %d\n", v)
+	return nil
+}
+
+func (s *shell) regions(ctx context.Context, args []string) error {
+	regions, err := s.client.GetTableRegionsContext(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return s.out.regions(regions)
+}
+
+func (s *shell) enable(ctx context.Context, args []string) error {
+	return s.client.EnableTableContext(ctx, args[0])
+}
+
+func (s *shell) disable(ctx context.Context, args []string) error {
+	return s.client.DisableTableContext(ctx, args[0])
+}
+
+func (s *shell) compact(ctx context.Context, args []string) error {
+	return s.client.CompactContext(ctx, args[0])
+}
+
+func (s *shell) majorCompact(ctx context.Context, args []string) error {
+	return s.client.MajorCompactContext(ctx, args[0])
+}
+
+func (s *shell) format(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(s.out.w, s.out.format)
+		return nil
+	}
+	switch args[0] {
+	case formatString, formatHex, formatTyped:
+		s.out.format = args[0]
+		return nil
+	}
+	return &usageError{cmd: lookup("format")}
+}
+
+func (s *shell) json(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		if s.out.json {
+			fmt.Fprintln(s.out.w, "on")
+		} else {
+			fmt.Fprintln(s.out.w, "off")
+		}
+		return nil
+	}
+	switch args[0] {
+	case "on":
+		s.out.json = true
+	case "off":
+		s.out.json = false
+	default:
+		return &usageError{cmd: lookup("json")}
+	}
+	return nil
+}
+
+func (s *shell) setType(ctx context.Context, args []string) error {
+	switch len(args) {
+	case 0:
+		names := make([]string, 0, len(s.out.types))
+		for name := range s.out.types {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(s.out.w, "%s %s\n", name, s.out.types[name])
+		}
+	case 1:
+		typ := s.out.typeOf(args[0])
+		if typ == "" {
+			typ = "binary"
+		}
+		fmt.Fprintln(s.out.w, typ)
+	default:
+		return declareType(s.out.types, args[0], args[1])
+	}
+	return nil
+}
+
+// declareType sets the type of column in types, none removing it.
+func declareType(types map[string]string, column, typ string) error {
+	if typ == "none" {
+		delete(types, column)
+		return nil
+	}
+	t, ok := valueTypes[strings.ToLower(typ)]
+	if !ok {
+		return fmt.Errorf("unknown type %q, see help type", typ)
+	}
+	types[column] = t
+	return nil
+}
+
+func (s *shell) showHistory(ctx context.Context, args []string) error {
+	for i, line := range s.hist.lines {
+		fmt.Fprintf(s.out.w, "%5d  %s\n", i+1, line)
+	}
+	return nil
+}
+
+func (s *shell) help(ctx context.Context, args []string) error {
+	if len(args) == 1 {
+		c := lookup(args[0])
+		if c == nil {
+			return fmt.Errorf("unknown command %q", args[0])
+		}
+		fmt.Fprintf(s.out.w, "%s\n  %s\n", c.usage, c.help)
+		return nil
+	}
+	for _, c := range commands {
+		fmt.Fprintf(s.out.w, "  %s\n", c.usage)
+	}
+	io.WriteString(s.out.w, `
+Arguments are separated by spaces. '...' quotes literally, "..." and bare
+arguments decode \xNN escapes, as keys are printed in the string format.
+`)
+	return nil
+}
+
+func (s *shell) exit(ctx context.Context, args []string) error {
+	return errExit
+}
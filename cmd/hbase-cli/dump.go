@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/J-J-J/hbase/dump"
+	"github.com/J-J-J/hbase/rowkey"
+)
+
+// keyRange return the start and stop options, or the range of the prefix
+// option.
+func keyRange(cmd string, opts map[string]string) (start, stop []byte, err error) {
+	prefix, ok := opts["prefix"]
+	if !ok {
+		return []byte(opts["start"]), []byte(opts["stop"]), nil
+	}
+	if opts["start"] != "" || opts["stop"] != "" {
+		return nil, nil, &usageError{cmd: lookup(cmd), msg: "prefix excludes start and stop"}
+	}
+	return []byte(prefix), rowkey.PrefixEnd([]byte(prefix)), nil
+}
+
+// columnList splits the columns option.
+func columnList(opts map[string]string) []string {
+	if columns := opts["columns"]; columns != "" {
+		return strings.Split(columns, ",")
+	}
+	return nil
+}
+
+// reportProgress prints a checkpoint on stderr.
+func reportProgress(verb string) func(cp dump.Checkpoint) error {
+	return func(cp dump.Checkpoint) error {
+		state := ""
+		if cp.Done {
+			state = ", done"
+		}
+		fmt.Fprintf(os.Stderr, "%s %d rows, %d cells, %d bytes in %s%s\n", verb, cp.Rows, cp.Cells, cp.Offset, cp.Elapsed.Round(time.Millisecond), state)
+		return nil
+	}
+}
+
+func (s *shell) export(ctx context.Context, args []string) error {
+	opts, rest := options(args[2:], "format", "start", "stop", "prefix", "columns", "versions")
+	if len(rest) > 0 {
+		return &usageError{cmd: lookup("export"), msg: "unknown option " + strconv.Quote(rest[0])}
+	}
+	eo := dump.ExportOptions{Columns: columnList(opts), Progress: reportProgress("exported")}
+	if f, ok := opts["format"]; ok {
+		if err := eo.Format.UnmarshalText([]byte(f)); err != nil {
+			return err
+		}
+	}
+	var err error
+	if eo.StartRow, eo.StopRow, err = keyRange("export", opts); err != nil {
+		return err
+	}
+	versions, err := intOption(opts, "versions", 0)
+	if err != nil {
+		return err
+	}
+	eo.MaxVersions = int32(versions)
+	return dump.ExportFileContext(ctx, s.client, args[0], args[1], eo)
+}
+
+func (s *shell) importFile(ctx context.Context, args []string) error {
+	opts, rest := options(args[2:], "start", "stop", "prefix", "columns")
+	if len(rest) > 0 {
+		return &usageError{cmd: lookup("import"), msg: "unknown option " + strconv.Quote(rest[0])}
+	}
+	iopts := dump.ImportOptions{Columns: columnList(opts), Progress: reportProgress("imported")}
+	var err error
+	if iopts.StartRow, iopts.StopRow, err = keyRange("import", opts); err != nil {
+		return err
+	}
+	return dump.ImportFileContext(ctx, s.client, args[0], args[1], iopts)
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/J-J-J/hbase"
+	"github.com/J-J-J/hbase/Hbase"
+	"github.com/J-J-J/hbase/codec"
+)
+
+// value formats
+const (
+	formatString = "string" // printable bytes, others escaped as \xNN
+	formatHex    = "hex"
+	formatTyped  = "typed" // decoded per the declared column types
+)
+
+// valueTypes are the types a column can be declared with, by Java name and
+// alias.
+var valueTypes = map[string]string{
+	"long": "long", "int64": "long",
+	"int": "int", "int32": "int",
+	"short": "short", "int16": "short",
+	"double": "double", "float64": "double",
+	"float": "float", "float32": "float",
+	"boolean": "boolean", "bool": "boolean",
+	"string": "string", "bigdecimal": "bigdecimal", "binary": "binary",
+}
+
+// printer writes results in the selected format.
+type printer struct {
+	w      io.Writer
+	format string
+	json   bool
+	// types maps a column, or a family, to its value type
+	types map[string]string
+}
+
+// toStringBinary formats b as Bytes.toStringBinary: letters, digits, space
+// and punctuation but the backslash are kept, other bytes are escaped as
+// \xNN.
+func toStringBinary(b []byte) string {
+	var s strings.Builder
+	for _, c := range b {
+		if c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || strings.IndexByte(" `~!@#$%^&*()-_=+[]{}|;:'\",.<>/?", c) >= 0 {
+			s.WriteByte(c)
+		} else {
+			fmt.Fprintf(&s, "\\x%02X", c)
+		}
+	}
+	return s.String()
+}
+
+// toBytesBinary parses the output of toStringBinary, as
+// Bytes.toBytesBinary: \xNN is the byte NN, anything else is literal.
+func toBytesBinary(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && s[i+1] == 'x' {
+			if n, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				b = append(b, byte(n))
+				i += 3
+				continue
+			}
+		}
+		b = append(b, s[i])
+	}
+	return b
+}
+
+// typeOf return the declared type of column, the type of its family
+// otherwise, "" when none.
+func (p *printer) typeOf(column string) string {
+	if t, ok := p.types[column]; ok {
+		return t
+	}
+	family, _, _ := strings.Cut(column, ":")
+	return p.types[family]
+}
+
+// key formats a row key or a column name.
+func (p *printer) key(b []byte) string {
+	if p.format == formatHex {
+		return hex.EncodeToString(b)
+	}
+	return toStringBinary(b)
+}
+
+// value return the text or JSON value of a cell of column.
+func (p *printer) value(column string, b []byte) interface{} {
+	switch p.format {
+	case formatHex:
+		return hex.EncodeToString(b)
+	case formatTyped:
+		v, err := decodeValue(p.typeOf(column), b)
+		if err != nil {
+			break
+		}
+		// JSON has no NaN nor infinities
+		switch f := v.(type) {
+		case float64:
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return strconv.FormatFloat(f, 'g', -1, 64)
+			}
+		case float32:
+			if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+				return strconv.FormatFloat(float64(f), 'g', -1, 32)
+			}
+		}
+		return v
+	}
+	return toStringBinary(b)
+}
+
+// decodeValue decodes b as typ, a type of valueTypes or "".
+func decodeValue(typ string, b []byte) (v interface{}, err error) {
+	switch typ {
+	case "long":
+		v, err = codec.DecodeInt64(b)
+	case "int":
+		v, err = codec.DecodeInt32(b)
+	case "short":
+		v, err = codec.DecodeInt16(b)
+	case "double":
+		v, err = codec.DecodeFloat64(b)
+	case "float":
+		v, err = codec.DecodeFloat32(b)
+	case "boolean":
+		v, err = codec.DecodeBool(b)
+	case "string":
+		v, err = codec.DecodeString(b)
+	case "bigdecimal":
+		var d codec.BigDecimal
+		if d, err = codec.DecodeBigDecimal(b); err == nil {
+			// as a string, a JSON number would lose precision
+			v = d.String()
+		}
+	default:
+		v = toStringBinary(b)
+	}
+	return
+}
+
+// encodeValue encodes the argument s as typ, binary when typ is "".
+func encodeValue(typ string, s string) ([]byte, error) {
+	switch typ {
+	case "long", "int", "short":
+		bits := map[string]int{"long": 64, "int": 32, "short": 16}[typ]
+		n, err := strconv.ParseInt(s, 0, bits)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", typ, s)
+		}
+		switch typ {
+		case "long":
+			return codec.EncodeInt64(n), nil
+		case "int":
+			return codec.EncodeInt32(int32(n)), nil
+		}
+		return codec.EncodeInt16(int16(n)), nil
+	case "double", "float":
+		bits := map[string]int{"double": 64, "float": 32}[typ]
+		f, err := strconv.ParseFloat(s, bits)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", typ, s)
+		}
+		if typ == "double" {
+			return codec.EncodeFloat64(f), nil
+		}
+		return codec.EncodeFloat32(float32(f)), nil
+	case "boolean":
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean %q", s)
+		}
+		return codec.EncodeBool(v), nil
+	case "bigdecimal":
+		d, err := codec.ParseBigDecimal(s)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeBigDecimal(d), nil
+	case "string":
+		return []byte(s), nil
+	}
+	return toBytesBinary(s), nil
+}
+
+// jsonCell is a cell in JSON output.
+type jsonCell struct {
+	Column    string      `json:"column"`
+	Timestamp int64       `json:"timestamp"`
+	Value     interface{} `json:"value"`
+}
+
+// jsonRow is a row in JSON output, one per line.
+type jsonRow struct {
+	Row   string     `json:"row"`
+	Cells []jsonCell `json:"cells"`
+}
+
+// cell is a cell of a row being printed.
+type cell struct {
+	column string
+	*Hbase.TCell
+}
+
+// sortedCells return the cells of row in column order.
+func sortedCells(row *Hbase.TRowResult) []cell {
+	cells := make([]cell, 0, len(row.Columns))
+	for column, c := range row.Columns {
+		cells = append(cells, cell{column, c})
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i].column < cells[j].column })
+	return cells
+}
+
+// row prints the cells of a row.
+func (p *printer) row(key []byte, cells []cell) error {
+	if p.json {
+		r := jsonRow{Row: p.key(key), Cells: make([]jsonCell, len(cells))}
+		for i, c := range cells {
+			r.Cells[i] = jsonCell{Column: p.key([]byte(c.column)), Timestamp: c.Timestamp, Value: p.value(c.column, c.Value)}
+		}
+		return p.encode(r)
+	}
+	k := p.key(key)
+	for _, c := range cells {
+		if _, err := fmt.Fprintf(p.w, " %s column=%s, timestamp=%d, value=%v\n", k, p.key([]byte(c.column)), c.Timestamp, p.value(c.column, c.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// count prints the number of rows printed, in text output.
+func (p *printer) count(n int) {
+	if !p.json {
+		fmt.Fprintf(p.w, "%d row(s)\n", n)
+	}
+}
+
+// encode writes v as a line of JSON.
+func (p *printer) encode(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = p.w.Write(append(b, '\n'))
+	return err
+}
+
+// jsonFamily is a column family in JSON output, in the form read by
+// schema.ParseJSON.
+type jsonFamily struct {
+	Name                  string `json:"name"`
+	MaxVersions           int32  `json:"maxVersions"`
+	Compression           string `json:"compression"`
+	InMemory              bool   `json:"inMemory"`
+	BloomFilterType       string `json:"bloomFilterType"`
+	BloomFilterVectorSize int32  `json:"bloomFilterVectorSize"`
+	BloomFilterNbHashes   int32  `json:"bloomFilterNbHashes"`
+	BlockCacheEnabled     bool   `json:"blockCacheEnabled"`
+	TimeToLive            int32  `json:"timeToLive"`
+}
+
+// describe prints a table and its families.
+func (p *printer) describe(table string, enabled bool, columns map[string]*hbase.ColumnDescriptor) error {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	families := make([]jsonFamily, len(names))
+	for i, name := range names {
+		cd := columns[name]
+		families[i] = jsonFamily{
+			Name:                  strings.TrimSuffix(name, ":"),
+			MaxVersions:           cd.MaxVersions,
+			Compression:           cd.Compression,
+			InMemory:              cd.InMemory,
+			BloomFilterType:       cd.BloomFilterType,
+			BloomFilterVectorSize: cd.BloomFilterVectorSize,
+			BloomFilterNbHashes:   cd.BloomFilterNbHashes,
+			BlockCacheEnabled:     cd.BlockCacheEnabled,
+			TimeToLive:            cd.TimeToLive,
+		}
+	}
+	if p.json {
+		return p.encode(struct {
+			Name     string       `json:"name"`
+			Enabled  bool         `json:"enabled"`
+			Families []jsonFamily `json:"families"`
+		}{table, enabled, families})
+	}
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+	}
+	fmt.Fprintf(p.w, "table %s is %s\n", table, state)
+	for _, f := range families {
+		fmt.Fprintf(p.w, "family %s: maxVersions=%d, compression=%s, inMemory=%t, bloomFilterType=%s, blockCacheEnabled=%t, timeToLive=%d\n",
+			f.Name, f.MaxVersions, f.Compression, f.InMemory, f.BloomFilterType, f.BlockCacheEnabled, f.TimeToLive)
+	}
+	return nil
+}
+
+// regions prints the regions of a table.
+func (p *printer) regions(regions []*hbase.TRegionInfo) error {
+	for _, r := range regions {
+		if p.json {
+			err := p.encode(struct {
+				Name       string `json:"name"`
+				StartKey   string `json:"startKey"`
+				EndKey     string `json:"endKey"`
+				Id         int64  `json:"id"`
+				ServerName string `json:"serverName"`
+				Port       int32  `json:"port"`
+			}{p.key([]byte(r.Name)), p.key([]byte(r.StartKey)), p.key([]byte(r.EndKey)), r.Id, r.ServerName, r.Port})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprintf(p.w, " %s start=%s, end=%s, server=%s:%d\n", p.key([]byte(r.Name)), p.key([]byte(r.StartKey)), p.key([]byte(r.EndKey)), r.ServerName, r.Port)
+	}
+	if !p.json {
+		fmt.Fprintf(p.w, "%d region(s)\n", len(regions))
+	}
+	return nil
+}
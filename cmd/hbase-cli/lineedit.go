@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxHistory is the number of lines kept in the history.
+const maxHistory = 1000
+
+// errInterrupted is returned by readLine when the line is abandoned with
+// Ctrl-C.
+var errInterrupted = errors.New("interrupted")
+
+// history is the list of lines entered, optionally persisted to a file.
+type history struct {
+	lines []string
+	path  string
+}
+
+// loadHistory return the history saved in path, empty when path is "" or
+// does not exist.
+func loadHistory(path string) *history {
+	h := &history{path: path}
+	if path == "" {
+		return h
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			h.lines = append(h.lines, line)
+		}
+	}
+	if len(h.lines) > maxHistory {
+		h.lines = h.lines[len(h.lines)-maxHistory:]
+	}
+	return h
+}
+
+// add appends line, unless it repeats the last one, and saves it.
+func (h *history) add(line string) {
+	if line == "" || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == line) {
+		return
+	}
+	h.lines = append(h.lines, line)
+	if len(h.lines) > maxHistory {
+		h.lines = h.lines[len(h.lines)-maxHistory:]
+	}
+	if h.path == "" {
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return
+	}
+	f.WriteString(line + "\n")
+	f.Close()
+}
+
+// lineEditor reads lines from a terminal in raw mode with emacs style
+// editing keys and history navigation.
+type lineEditor struct {
+	fd   int
+	in   *bufio.Reader
+	out  io.Writer
+	hist *history
+
+	prompt string
+	line   []rune
+	pos    int
+}
+
+func newLineEditor(in *os.File, out io.Writer, hist *history) *lineEditor {
+	return &lineEditor{fd: int(in.Fd()), in: bufio.NewReader(in), out: out, hist: hist}
+}
+
+// readLine reads a line after showing prompt. The terminal is in raw mode
+// only while the line is edited. It return io.EOF on Ctrl-D on an empty
+// line and errInterrupted on Ctrl-C.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	state, err := makeRaw(e.fd)
+	if err != nil {
+		return "", err
+	}
+	defer restore(e.fd, state)
+
+	e.prompt, e.line, e.pos = prompt, nil, 0
+	// browsing the history edits copies, the entry past the last one being
+	// the new line
+	entries := append(append([]string(nil), e.hist.lines...), "")
+	current := len(entries) - 1
+	e.refresh()
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch r {
+		case '\r', '\n':
+			io.WriteString(e.out, "\r\n")
+			return string(e.line), nil
+		case 3: // Ctrl-C
+			io.WriteString(e.out, "^C\r\n")
+			return "", errInterrupted
+		case 4: // Ctrl-D
+			if len(e.line) == 0 {
+				io.WriteString(e.out, "\r\n")
+				return "", io.EOF
+			}
+			e.deleteAt(e.pos)
+		case 127, 8: // Backspace, Ctrl-H
+			if e.pos > 0 {
+				e.pos--
+				e.deleteAt(e.pos)
+			}
+		case 1: // Ctrl-A
+			e.pos = 0
+		case 5: // Ctrl-E
+			e.pos = len(e.line)
+		case 2: // Ctrl-B
+			e.left()
+		case 6: // Ctrl-F
+			e.right()
+		case 11: // Ctrl-K
+			e.line = e.line[:e.pos]
+		case 21: // Ctrl-U
+			e.line = append([]rune(nil), e.line[e.pos:]...)
+			e.pos = 0
+		case 23: // Ctrl-W
+			start := e.pos
+			for start > 0 && e.line[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && e.line[start-1] != ' ' {
+				start--
+			}
+			e.line = append(e.line[:start], e.line[e.pos:]...)
+			e.pos = start
+		case 12: // Ctrl-L
+			io.WriteString(e.out, "\x1b[H\x1b[2J")
+		case 16: // Ctrl-P
+			current = e.browse(entries, current, current-1)
+		case 14: // Ctrl-N
+			current = e.browse(entries, current, current+1)
+		case 27: // escape sequence
+			switch e.escape() {
+			case 'A':
+				current = e.browse(entries, current, current-1)
+			case 'B':
+				current = e.browse(entries, current, current+1)
+			case 'C':
+				e.right()
+			case 'D':
+				e.left()
+			case 'H':
+				e.pos = 0
+			case 'F':
+				e.pos = len(e.line)
+			case '3': // Delete
+				e.deleteAt(e.pos)
+			}
+		default:
+			if !unicode.IsPrint(r) {
+				continue
+			}
+			e.line = append(e.line, 0)
+			copy(e.line[e.pos+1:], e.line[e.pos:])
+			e.line[e.pos] = r
+			e.pos++
+		}
+		e.refresh()
+	}
+}
+
+// escape reads the rest of an escape sequence and return its final byte,
+// or '3' for Delete.
+func (e *lineEditor) escape() byte {
+	b, err := e.in.ReadByte()
+	if err != nil || (b != '[' && b != 'O') {
+		return 0
+	}
+	var params []byte
+	for {
+		c, err := e.in.ReadByte()
+		if err != nil {
+			return 0
+		}
+		if c >= 0x40 && c <= 0x7e {
+			if c == '~' && string(params) == "3" {
+				return '3'
+			}
+			if c == '~' && (string(params) == "1" || string(params) == "7") {
+				return 'H'
+			}
+			if c == '~' && (string(params) == "4" || string(params) == "8") {
+				return 'F'
+			}
+			return c
+		}
+		params = append(params, c)
+	}
+}
+
+// browse replaces the line with the history entry to, saving the edits of
+// the entry from, and return the entry shown.
+func (e *lineEditor) browse(entries []string, from, to int) int {
+	if to < 0 || to >= len(entries) {
+		return from
+	}
+	entries[from] = string(e.line)
+	e.line = []rune(entries[to])
+	e.pos = len(e.line)
+	return to
+}
+
+func (e *lineEditor) left() {
+	if e.pos > 0 {
+		e.pos--
+	}
+}
+
+func (e *lineEditor) right() {
+	if e.pos < len(e.line) {
+		e.pos++
+	}
+}
+
+func (e *lineEditor) deleteAt(i int) {
+	if i < len(e.line) {
+		e.line = append(e.line[:i], e.line[i+1:]...)
+	}
+}
+
+// refresh redraws the line and moves the cursor to its position.
+func (e *lineEditor) refresh() {
+	var b strings.Builder
+	b.WriteString("\r")
+	b.WriteString(e.prompt)
+	b.WriteString(string(e.line))
+	b.WriteString("\x1b[K\r")
+	if col := len([]rune(e.prompt)) + e.pos; col > 0 {
+		b.WriteString("\x1b[" + strconv.Itoa(col) + "C")
+	}
+	io.WriteString(e.out, b.String())
+}
@@ -0,0 +1,233 @@
+// Command hbase-cli is a shell for HBase through its thrift gateway, for
+// when the Java shell is not at hand.
+//
+//	hbase-cli -addr gateway:9090
+//	hbase> scan events prefix=user42 limit=10
+//	hbase> get events "\x00\x01row" d:count versions=3
+//
+// Run interactively it edits lines with the usual emacs keys and keeps a
+// history in ~/.hbase_cli_history. Otherwise it reads one command per line
+// from stdin, stopping at the first failure unless -k is given, or runs
+// the command given as arguments:
+//
+//	hbase-cli -addr gateway:9090 -json scan events limit=100 | jq .row
+//
+// Keys and values are printed as Bytes.toStringBinary does, in hex, or
+// decoded per the types declared with -type or the type command. See the
+// help command for the list of commands.
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/J-J-J/hbase"
+)
+
+// typeFlags collects the -type column=type flags.
+type typeFlags map[string]string
+
+func (t typeFlags) String() string {
+	return ""
+}
+
+func (t typeFlags) Set(s string) error {
+	column, typ, ok := strings.Cut(s, "=")
+	if !ok {
+		return errors.New("want column=type")
+	}
+	return declareType(t, column, typ)
+}
+
+func main() {
+	var (
+		addr     = flag.String("addr", "localhost:9090", "thrift gateway `host:port`")
+		httpURL  = flag.String("http", "", "thrift gateway `url`, for a gateway in -http mode")
+		useTLS   = flag.Bool("tls", false, "connect with TLS")
+		framed   = flag.Bool("framed", false, "use the framed transport")
+		compact  = flag.Bool("compact", false, "use the compact protocol")
+		timeout  = flag.Duration("timeout", 0, "time limit of every command, 0 for none")
+		format   = flag.String("format", formatString, "print keys and values as `string`, hex or typed")
+		jsonOut  = flag.Bool("json", false, "print JSON, one document per line")
+		keepOn   = flag.Bool("k", false, "keep running the commands read from stdin after a failure")
+		noCheck  = flag.Bool("novalidate", false, "send the filter strings to the gateway without parsing them first")
+		histFile = flag.String("history", defaultHistory(), "history `file`, empty to keep none")
+		types    = typeFlags{}
+	)
+	flag.Var(types, "type", "declare the value type of a `column=type` or family=type, repeatable")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [command [arguments]]\n", filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	switch *format {
+	case formatString, formatHex, formatTyped:
+	default:
+		fatal(fmt.Errorf("invalid -format %q", *format))
+	}
+
+	var opts []hbase.ClientOption
+	if !*noCheck {
+		opts = append(opts, hbase.WithFilterValidation())
+	}
+	if *framed {
+		opts = append(opts, hbase.WithFramedTransport(0))
+	}
+	if *compact {
+		opts = append(opts, hbase.WithCompactProtocol())
+	}
+	var client *hbase.HClient
+	var err error
+	switch {
+	case *httpURL != "":
+		client, err = hbase.NewHTTPClient(*httpURL, opts...)
+	case *useTLS:
+		client, err = hbase.NewTLSClient(*addr, &tls.Config{}, true, opts...)
+	default:
+		client, err = hbase.NewTCPClient(*addr, true, opts...)
+	}
+	if err == nil {
+		err = client.Open()
+	}
+	if err != nil {
+		fatal(err)
+	}
+	defer client.Close()
+
+	s := &shell{
+		client: client,
+		out:    &printer{w: os.Stdout, format: *format, json: *jsonOut, types: types},
+		hist:   &history{},
+	}
+	r := &runner{shell: s, timeout: *timeout}
+	signal.Notify(r.interrupt(), os.Interrupt)
+
+	switch {
+	case flag.NArg() > 0:
+		args := make([]string, flag.NArg())
+		for i, arg := range flag.Args() {
+			args[i] = string(toBytesBinary(arg))
+		}
+		err = r.run(func(ctx context.Context) error { return s.runArgs(ctx, args) })
+	case isTerminal(int(os.Stdin.Fd())) && isTerminal(int(os.Stdout.Fd())):
+		s.hist = loadHistory(*histFile)
+		err = r.interactive()
+	default:
+		err = r.script(os.Stdin, *keepOn)
+	}
+	if err != nil && err != errExit {
+		client.Close()
+		fatal(err)
+	}
+}
+
+func defaultHistory() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".hbase_cli_history")
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "hbase-cli:", err)
+	os.Exit(1)
+}
+
+// runner runs the commands of a shell, each under its own context that
+// Ctrl-C and the timeout cancel.
+type runner struct {
+	shell   *shell
+	timeout time.Duration
+	sig     chan os.Signal
+}
+
+func (r *runner) interrupt() chan os.Signal {
+	r.sig = make(chan os.Signal, 1)
+	return r.sig
+}
+
+// run runs fn under a context cancelled by an interrupt.
+func (r *runner) run(fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if r.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-r.sig:
+			cancel()
+		case <-done:
+		}
+	}()
+	return fn(ctx)
+}
+
+// interactive reads and runs commands from the terminal until exit or
+// Ctrl-D, reporting failures.
+func (r *runner) interactive() error {
+	editor := newLineEditor(os.Stdin, os.Stdout, r.shell.hist)
+	fmt.Printf("connected to %s, type help for the commands\n", r.shell.client.Addr())
+	for {
+		line, err := editor.readLine("hbase> ")
+		switch {
+		case err == io.EOF:
+			return nil
+		case err == errInterrupted:
+			continue
+		case err != nil:
+			return err
+		}
+		r.shell.hist.add(strings.TrimSpace(line))
+		err = r.run(func(ctx context.Context) error { return r.shell.runLine(ctx, line) })
+		if err == errExit {
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ERROR:", err)
+		}
+	}
+}
+
+// script runs the commands read from in, stopping at the first failure
+// unless keepOn.
+func (r *runner) script(in io.Reader, keepOn bool) error {
+	sc := bufio.NewScanner(in)
+	sc.Buffer(nil, 1<<20)
+	failed := false
+	for num := 1; sc.Scan(); num++ {
+		line := sc.Text()
+		err := r.run(func(ctx context.Context) error { return r.shell.runLine(ctx, line) })
+		if err == errExit {
+			break
+		}
+		if err != nil {
+			if !keepOn {
+				return fmt.Errorf("line %d: %v", num, err)
+			}
+			fmt.Fprintf(os.Stderr, "hbase-cli: line %d: %v\n", num, err)
+			failed = true
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if failed {
+		return errors.New("some commands failed")
+	}
+	return nil
+}
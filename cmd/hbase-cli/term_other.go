@@ -0,0 +1,22 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package main
+
+import "errors"
+
+// termState is the saved mode of a terminal.
+type termState struct{}
+
+// isTerminal reports whether fd is a terminal, never on this platform: the
+// shell reads lines without editing.
+func isTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (*termState, error) {
+	return nil, errors.New("raw terminal mode is not supported")
+}
+
+func restore(fd int, state *termState) error {
+	return nil
+}
@@ -0,0 +1,51 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// termState is the saved mode of a terminal.
+type termState struct {
+	termios syscall.Termios
+}
+
+func ioctlTermios(fd int, req uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), req, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// isTerminal reports whether fd is a terminal.
+func isTerminal(fd int) bool {
+	var t syscall.Termios
+	return ioctlTermios(fd, ioctlGetTermios, &t) == nil
+}
+
+// makeRaw puts the terminal fd in raw mode, keeping output processing, and
+// return its previous state.
+func makeRaw(fd int) (*termState, error) {
+	var old termState
+	if err := ioctlTermios(fd, ioctlGetTermios, &old.termios); err != nil {
+		return nil, err
+	}
+	raw := old.termios
+	raw.Iflag &^= syscall.BRKINT | syscall.ICRNL | syscall.INPCK | syscall.ISTRIP | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.IEXTEN | syscall.ISIG
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := ioctlTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	return &old, nil
+}
+
+// restore puts the terminal fd back in state.
+func restore(fd int, state *termState) error {
+	return ioctlTermios(fd, ioctlSetTermios, &state.termios)
+}
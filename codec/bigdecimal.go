@@ -0,0 +1,165 @@
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// BigDecimal is a java.math.BigDecimal, the number Unscaled * 10^-Scale.
+// Like Java, 1.0 and 1.00 are different values of the same number.
+type BigDecimal struct {
+	Unscaled *big.Int // nil is zero
+	Scale    int32
+}
+
+// NewBigDecimal return unscaled * 10^-scale.
+func NewBigDecimal(unscaled int64, scale int32) BigDecimal {
+	return BigDecimal{Unscaled: big.NewInt(unscaled), Scale: scale}
+}
+
+// ParseBigDecimal parses a number the way new BigDecimal(String) does, as in
+// "-12.50" or "1.5E+3", keeping its scale.
+func ParseBigDecimal(s string) (BigDecimal, error) {
+	mantissa, exp := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		mantissa = s[:i]
+		if exp, err = strconv.ParseInt(s[i+1:], 10, 32); err != nil {
+			return BigDecimal{}, errors.New("codec: invalid BigDecimal exponent in " + strconv.Quote(s))
+		}
+	}
+	digits, scale := mantissa, int64(0)
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		digits = mantissa[:i] + mantissa[i+1:]
+		scale = int64(len(mantissa) - i - 1)
+	}
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return BigDecimal{}, errors.New("codec: invalid BigDecimal " + strconv.Quote(s))
+	}
+	scale -= exp
+	if scale < -1<<31 || scale > 1<<31-1 {
+		return BigDecimal{}, errors.New("codec: BigDecimal scale out of range in " + strconv.Quote(s))
+	}
+	return BigDecimal{Unscaled: unscaled, Scale: int32(scale)}, nil
+}
+
+func (d BigDecimal) unscaled() *big.Int {
+	if d.Unscaled == nil {
+		return new(big.Int)
+	}
+	return d.Unscaled
+}
+
+// Rat return the exact value of d.
+func (d BigDecimal) Rat() *big.Rat {
+	r := new(big.Rat).SetInt(d.unscaled())
+	pow := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(abs(int64(d.Scale))), nil))
+	if d.Scale >= 0 {
+		return r.Quo(r, pow)
+	}
+	return r.Mul(r, pow)
+}
+
+// Float64 return the nearest float64 to d.
+func (d BigDecimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// String formats d without an exponent, as toPlainString does.
+func (d BigDecimal) String() string {
+	u := d.unscaled()
+	digits := new(big.Int).Abs(u).String()
+	sign := ""
+	if u.Sign() < 0 {
+		sign = "-"
+	}
+	if d.Scale <= 0 {
+		if u.Sign() == 0 {
+			return "0"
+		}
+		return sign + digits + strings.Repeat("0", int(-d.Scale))
+	}
+	scale := int(d.Scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// EncodeBigDecimal encodes d as Bytes.toBytes(BigDecimal): the scale as an
+// int followed by the unscaled value in two's complement, as
+// BigInteger.toByteArray.
+func EncodeBigDecimal(d BigDecimal) []byte {
+	mag := twosComplement(d.unscaled())
+	b := make([]byte, SizeofInt, SizeofInt+len(mag))
+	binary.BigEndian.PutUint32(b, uint32(d.Scale))
+	return append(b, mag...)
+}
+
+// DecodeBigDecimal decodes a value of Bytes.toBytes(BigDecimal).
+func DecodeBigDecimal(b []byte) (BigDecimal, error) {
+	if len(b) < SizeofInt+1 {
+		return BigDecimal{}, &LengthError{Type: "BigDecimal", Want: SizeofInt + 1, Got: len(b), AtLeast: true}
+	}
+	return BigDecimal{
+		Unscaled: fromTwosComplement(b[SizeofInt:]),
+		Scale:    int32(binary.BigEndian.Uint32(b)),
+	}, nil
+}
+
+// twosComplement return the shortest big-endian two's complement of n, at
+// least one byte.
+func twosComplement(n *big.Int) []byte {
+	if n.Sign() >= 0 {
+		b := n.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return b
+	}
+	// -n - 1 with its bits inverted is n
+	b := new(big.Int).Sub(new(big.Int).Neg(n), big.NewInt(1)).Bytes()
+	for i := range b {
+		b[i] = ^b[i]
+	}
+	if len(b) == 0 || b[0]&0x80 == 0 {
+		b = append([]byte{0xff}, b...)
+	}
+	return b
+}
+
+func fromTwosComplement(b []byte) *big.Int {
+	if b[0]&0x80 == 0 {
+		return new(big.Int).SetBytes(b)
+	}
+	inv := make([]byte, len(b))
+	for i := range b {
+		inv[i] = ^b[i]
+	}
+	n := new(big.Int).SetBytes(inv)
+	return n.Neg(n.Add(n, big.NewInt(1)))
+}
+
+// MarshalHbase encodes d with EncodeBigDecimal, for struct fields mapped by
+// hbase.Marshal.
+func (d BigDecimal) MarshalHbase() ([]byte, error) {
+	return EncodeBigDecimal(d), nil
+}
+
+// UnmarshalHbase decodes b with DecodeBigDecimal, for hbase.Unmarshal.
+func (d *BigDecimal) UnmarshalHbase(b []byte) (err error) {
+	*d, err = DecodeBigDecimal(b)
+	return
+}
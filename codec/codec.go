@@ -0,0 +1,173 @@
+// Package codec encodes and decodes cell values byte for byte like the
+// org.apache.hadoop.hbase.util.Bytes class of the Java client, so that Go
+// and Java services read each other's cells:
+//
+//	mutation := hbase.NewMutation("cf:count", codec.EncodeInt64(42))
+//	...
+//	n, err := codec.DecodeInt64(cell.Value)
+//
+// Decoding checks the length of the value and fails with a *LengthError,
+// where Java would read the leading bytes of a longer value.
+package codec
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Sizes of the fixed size encodings, as the SIZEOF_ constants of Bytes.
+const (
+	SizeofBoolean = 1
+	SizeofShort   = 2
+	SizeofInt     = 4
+	SizeofFloat   = 4
+	SizeofLong    = 8
+	SizeofDouble  = 8
+)
+
+// LengthError is a value whose length does not suit its type.
+type LengthError struct {
+	Type    string // Go type being decoded
+	Want    int
+	Got     int
+	AtLeast bool // Want is a minimum
+}
+
+func (e *LengthError) Error() string {
+	want := strconv.Itoa(e.Want)
+	if e.AtLeast {
+		want = "at least " + want
+	}
+	return "codec: " + e.Type + " needs " + want + " bytes, got " + strconv.Itoa(e.Got)
+}
+
+// InvalidUTF8Error is a string value that is not valid UTF-8.
+type InvalidUTF8Error struct {
+	Offset int // offset of the first invalid byte
+}
+
+func (e *InvalidUTF8Error) Error() string {
+	return "codec: invalid UTF-8 at offset " + strconv.Itoa(e.Offset)
+}
+
+func checkLength(typ string, b []byte, size int) error {
+	if len(b) != size {
+		return &LengthError{Type: typ, Want: size, Got: len(b)}
+	}
+	return nil
+}
+
+// EncodeInt64 encodes n as Bytes.toBytes(long).
+func EncodeInt64(n int64) []byte {
+	b := make([]byte, SizeofLong)
+	binary.BigEndian.PutUint64(b, uint64(n))
+	return b
+}
+
+// DecodeInt64 decodes a value of Bytes.toBytes(long), or an AtomicIncrement
+// counter.
+func DecodeInt64(b []byte) (int64, error) {
+	if err := checkLength("int64", b, SizeofLong); err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(b)), nil
+}
+
+// EncodeInt32 encodes n as Bytes.toBytes(int).
+func EncodeInt32(n int32) []byte {
+	b := make([]byte, SizeofInt)
+	binary.BigEndian.PutUint32(b, uint32(n))
+	return b
+}
+
+// DecodeInt32 decodes a value of Bytes.toBytes(int).
+func DecodeInt32(b []byte) (int32, error) {
+	if err := checkLength("int32", b, SizeofInt); err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint32(b)), nil
+}
+
+// EncodeInt16 encodes n as Bytes.toBytes(short).
+func EncodeInt16(n int16) []byte {
+	b := make([]byte, SizeofShort)
+	binary.BigEndian.PutUint16(b, uint16(n))
+	return b
+}
+
+// DecodeInt16 decodes a value of Bytes.toBytes(short).
+func DecodeInt16(b []byte) (int16, error) {
+	if err := checkLength("int16", b, SizeofShort); err != nil {
+		return 0, err
+	}
+	return int16(binary.BigEndian.Uint16(b)), nil
+}
+
+// EncodeFloat64 encodes f as Bytes.toBytes(double), NaN payloads included.
+func EncodeFloat64(f float64) []byte {
+	b := make([]byte, SizeofDouble)
+	binary.BigEndian.PutUint64(b, math.Float64bits(f))
+	return b
+}
+
+// DecodeFloat64 decodes a value of Bytes.toBytes(double).
+func DecodeFloat64(b []byte) (float64, error) {
+	if err := checkLength("float64", b, SizeofDouble); err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+}
+
+// EncodeFloat32 encodes f as Bytes.toBytes(float).
+func EncodeFloat32(f float32) []byte {
+	b := make([]byte, SizeofFloat)
+	binary.BigEndian.PutUint32(b, math.Float32bits(f))
+	return b
+}
+
+// DecodeFloat32 decodes a value of Bytes.toBytes(float).
+func DecodeFloat32(b []byte) (float32, error) {
+	if err := checkLength("float32", b, SizeofFloat); err != nil {
+		return 0, err
+	}
+	return math.Float32frombits(binary.BigEndian.Uint32(b)), nil
+}
+
+// EncodeBool encodes v as Bytes.toBytes(boolean), true being 0xff.
+func EncodeBool(v bool) []byte {
+	if v {
+		return []byte{0xff}
+	}
+	return []byte{0}
+}
+
+// DecodeBool decodes a value of Bytes.toBytes(boolean), any non-zero byte
+// being true.
+func DecodeBool(b []byte) (bool, error) {
+	if err := checkLength("bool", b, SizeofBoolean); err != nil {
+		return false, err
+	}
+	return b[0] != 0, nil
+}
+
+// EncodeString encodes s as Bytes.toBytes(String), in UTF-8.
+func EncodeString(s string) []byte {
+	return []byte(s)
+}
+
+// DecodeString decodes a value of Bytes.toBytes(String). Java replaces
+// invalid UTF-8 with U+FFFD, DecodeString fails instead.
+func DecodeString(b []byte) (string, error) {
+	if !utf8.Valid(b) {
+		for i := 0; i < len(b); {
+			r, size := utf8.DecodeRune(b[i:])
+			if r == utf8.RuneError && size == 1 {
+				return "", &InvalidUTF8Error{Offset: i}
+			}
+			i += size
+		}
+	}
+	return string(b), nil
+}
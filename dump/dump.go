@@ -0,0 +1,321 @@
+// Package dump copies the cells of a table, every version with its
+// timestamp, to a file and back, for debugging, test fixtures and moving
+// data between clusters.
+//
+//	err := dump.ExportFile(client, "events", "events.dump", dump.ExportOptions{
+//		Format:   dump.Binary,
+//		StartRow: []byte("user42"),
+//		StopRow:  []byte("user43"),
+//	})
+//	...
+//	err = dump.ImportFile(client, "events_copy", "events.dump", dump.ImportOptions{})
+//
+// A dump is a sequence of rows in one of two formats. JSONL writes a row
+// per line as the JSON of a Row, keys and values in base64:
+//
+//	{"row":"dXNlcjQy","cells":[{"column":"ZDpu","timestamp":1700000000000,"value":"AQ=="}]}
+//
+// Binary starts with the 8 bytes "HBDUMP\x00\x01", followed by the rows,
+// every length being an unsigned varint:
+//
+//	row     length, key, number of cells, cells
+//	cell    length, column, timestamp as 8 bytes big-endian, length, value
+//
+// ExportFile and ImportFile save a Checkpoint next to the dump after every
+// batch of rows, and a run interrupted for any reason continues from it when
+// started again.
+package dump
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// Format is the encoding of a dump.
+type Format int
+
+const (
+	JSONL Format = iota
+	Binary
+)
+
+func (f Format) String() string {
+	switch f {
+	case JSONL:
+		return "jsonl"
+	case Binary:
+		return "binary"
+	}
+	return "Format(" + strconv.Itoa(int(f)) + ")"
+}
+
+// MarshalText makes a Format readable in a saved Checkpoint.
+func (f Format) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText parses a Format, as written by MarshalText.
+func (f *Format) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "jsonl":
+		*f = JSONL
+	case "binary":
+		*f = Binary
+	default:
+		return errors.New("dump: unknown format " + strconv.Quote(string(text)))
+	}
+	return nil
+}
+
+// magic starts a dump in the Binary format.
+const magic = "HBDUMP\x00\x01"
+
+// maxField bounds the length of a key or value read, so that a corrupt
+// dump fails instead of allocating without limit.
+const maxField = 1 << 28
+
+// ErrCorrupt is returned when reading an invalid dump.
+var ErrCorrupt = errors.New("dump: invalid dump")
+
+// Row is a row of a dump.
+type Row struct {
+	Row   []byte `json:"row"`
+	Cells []Cell `json:"cells"`
+}
+
+// Cell is a version of a column.
+type Cell struct {
+	Column    []byte `json:"column"` // family:qualifier
+	Timestamp int64  `json:"timestamp"`
+	Value     []byte `json:"value"`
+}
+
+// Writer writes a dump.
+type Writer struct {
+	w      *bufio.Writer
+	format Format
+	offset int64
+	buf    []byte
+}
+
+// NewWriter return a Writer writing to w in format, starting with the
+// header of the format.
+func NewWriter(w io.Writer, format Format) *Writer {
+	return newWriter(w, format, 0)
+}
+
+// newWriter return a Writer continuing a dump at offset, the header being
+// written at offset 0 only.
+func newWriter(w io.Writer, format Format, offset int64) *Writer {
+	dw := &Writer{w: bufio.NewWriter(w), format: format, offset: offset}
+	if format == Binary && offset == 0 {
+		dw.buf = append(dw.buf, magic...)
+	}
+	return dw
+}
+
+// Write writes row.
+func (w *Writer) Write(row *Row) error {
+	switch w.format {
+	case JSONL:
+		b, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		w.buf = append(append(w.buf, b...), '\n')
+	case Binary:
+		b := appendField(w.buf, row.Row)
+		b = binary.AppendUvarint(b, uint64(len(row.Cells)))
+		for _, c := range row.Cells {
+			b = appendField(b, c.Column)
+			b = binary.BigEndian.AppendUint64(b, uint64(c.Timestamp))
+			b = appendField(b, c.Value)
+		}
+		w.buf = b
+	default:
+		return errors.New("dump: unknown format " + w.format.String())
+	}
+	n, err := w.w.Write(w.buf)
+	w.offset += int64(n)
+	w.buf = w.buf[:0]
+	return err
+}
+
+func appendField(b, field []byte) []byte {
+	return append(binary.AppendUvarint(b, uint64(len(field))), field...)
+}
+
+// Flush writes the buffered rows to the underlying writer.
+func (w *Writer) Flush() error {
+	if len(w.buf) > 0 {
+		// the header of a dump without rows
+		n, err := w.w.Write(w.buf)
+		w.offset += int64(n)
+		w.buf = w.buf[:0]
+		if err != nil {
+			return err
+		}
+	}
+	return w.w.Flush()
+}
+
+// Offset return the number of bytes of the dump written, the buffered ones
+// included.
+func (w *Writer) Offset() int64 {
+	return w.offset
+}
+
+// Reader reads a dump.
+type Reader struct {
+	r      *bufio.Reader
+	format Format
+	offset int64
+}
+
+// NewReader return a Reader of the dump r, detecting its format.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len(magic))
+	switch {
+	case string(head) == magic:
+		br.Discard(len(magic))
+		return &Reader{r: br, format: Binary, offset: int64(len(magic))}, nil
+	case len(head) > 0 && head[0] == '{':
+		return &Reader{r: br, format: JSONL}, nil
+	case len(head) == 0 && err == io.EOF:
+		return &Reader{r: br, format: JSONL}, nil
+	}
+	return nil, ErrCorrupt
+}
+
+// newReaderAt return a Reader of the rows of a dump in format, r being
+// positioned at offset, at a row.
+func newReaderAt(r io.Reader, format Format, offset int64) *Reader {
+	return &Reader{r: bufio.NewReader(r), format: format, offset: offset}
+}
+
+// Format return the format of the dump.
+func (r *Reader) Format() Format {
+	return r.format
+}
+
+// Offset return the number of bytes of the dump read, up to the end of the
+// last row returned.
+func (r *Reader) Offset() int64 {
+	return r.offset
+}
+
+// Read return the next row, io.EOF at the end of the dump.
+func (r *Reader) Read() (*Row, error) {
+	if r.format == JSONL {
+		return r.readJSON()
+	}
+	return r.readBinary()
+}
+
+func (r *Reader) readJSON() (*Row, error) {
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if err == io.EOF && len(line) > 0 {
+			// a last row without newline, or one cut short
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.offset += int64(len(line))
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		row := &Row{}
+		if err := json.Unmarshal(line, row); err != nil {
+			return nil, errors.New("dump: at offset " + strconv.FormatInt(r.offset-int64(len(line)), 10) + ": " + err.Error())
+		}
+		return row, nil
+	}
+}
+
+func (r *Reader) readBinary() (*Row, error) {
+	cr := &countingReader{r: r.r}
+	key, err := cr.field()
+	if err != nil {
+		if err == io.EOF && cr.n == 0 {
+			return nil, io.EOF
+		}
+		return nil, corrupt(err)
+	}
+	count, err := binary.ReadUvarint(cr)
+	if err != nil || count > maxField {
+		return nil, corrupt(err)
+	}
+	row := &Row{Row: key, Cells: make([]Cell, 0, min(count, 1024))}
+	for i := uint64(0); i < count; i++ {
+		var c Cell
+		if c.Column, err = cr.field(); err != nil {
+			return nil, corrupt(err)
+		}
+		var ts [8]byte
+		if _, err = io.ReadFull(cr, ts[:]); err != nil {
+			return nil, corrupt(err)
+		}
+		c.Timestamp = int64(binary.BigEndian.Uint64(ts[:]))
+		if c.Value, err = cr.field(); err != nil {
+			return nil, corrupt(err)
+		}
+		row.Cells = append(row.Cells, c)
+	}
+	r.offset += cr.n
+	return row, nil
+}
+
+// corrupt return the error of a row cut short or invalid.
+func corrupt(err error) error {
+	if err == nil || err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrCorrupt
+	}
+	return err
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+// field reads a length prefixed field.
+func (c *countingReader) field() ([]byte, error) {
+	n, err := binary.ReadUvarint(c)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxField {
+		return nil, ErrCorrupt
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(c, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return b, nil
+}
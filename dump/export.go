@@ -0,0 +1,194 @@
+package dump
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/J-J-J/hbase"
+	"github.com/J-J-J/hbase/Hbase"
+)
+
+// DefaultBatchRows is the number of rows between two checkpoints when
+// BatchRows is not set.
+const DefaultBatchRows = 1000
+
+// Checkpoint is the position of an export or an import after a batch of
+// rows, reported to Progress. Resuming from it continues after LastRow.
+type Checkpoint struct {
+	Format  Format `json:"format"`
+	LastRow []byte `json:"lastRow"` // nil before the first row
+	Offset  int64  `json:"offset"`  // bytes of the dump written or read
+	Rows    int64  `json:"rows"`
+	Cells   int64  `json:"cells"`
+	// Elapsed is the time spent since the start, resumed runs included.
+	Elapsed time.Duration `json:"elapsed"`
+	Done    bool          `json:"done"`
+}
+
+// ExportOptions are the options of Export.
+type ExportOptions struct {
+	Format Format
+	// StartRow and StopRow bound the rows exported, StopRow excluded, an
+	// empty bound not limiting.
+	StartRow []byte
+	StopRow  []byte
+	// Columns selects families or family:qualifier columns, all when empty.
+	Columns []string
+	// MaxVersions is the number of versions exported per column, 0 for as
+	// many as the family keeps.
+	MaxVersions int32
+	// BatchRows is the number of rows between two calls to Progress,
+	// DefaultBatchRows when <= 0. It is also the scanner caching.
+	BatchRows int
+	// Progress is called after every batch of rows, the dump being flushed
+	// up to cp.Offset, and once more at the end with cp.Done set. An error
+	// stops the export.
+	Progress func(cp Checkpoint) error
+	// Resume continues an export from a checkpoint, the writer being
+	// positioned at its Offset.
+	Resume *Checkpoint
+	// Attributes are passed to the scanner.
+	Attributes map[string]string
+}
+
+// Export writes the rows of tableName to w. Rows are read with a resumable
+// scanner, which returns the latest version of every cell; when more
+// versions are exported the versions of every column are then read with
+// GetVer.
+func Export(client *hbase.HClient, tableName string, w io.Writer, opts ExportOptions) error {
+	return ExportContext(context.Background(), client, tableName, w, opts)
+}
+
+// ExportContext is Export with a context.
+func ExportContext(ctx context.Context, client *hbase.HClient, tableName string, w io.Writer, opts ExportOptions) error {
+	batch := opts.BatchRows
+	if batch <= 0 {
+		batch = DefaultBatchRows
+	}
+	cp := Checkpoint{Format: opts.Format}
+	if opts.Resume != nil {
+		cp = *opts.Resume
+		if cp.Done {
+			return nil
+		}
+	}
+	start := time.Now().Add(-cp.Elapsed)
+
+	versions, err := familyVersions(ctx, client, tableName, opts.MaxVersions)
+	if err != nil {
+		return err
+	}
+	scan := &hbase.TScan{
+		StartRow: opts.StartRow,
+		StopRow:  opts.StopRow,
+		Columns:  opts.Columns,
+		Caching:  int32(min(batch, 10000)),
+	}
+	if cp.LastRow != nil {
+		scan.StartRow = append(append([]byte(nil), cp.LastRow...), 0)
+	}
+	sc, err := client.ResumableScanContext(ctx, tableName, scan, opts.Attributes)
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+
+	dw := newWriter(w, cp.Format, cp.Offset)
+	report := func() error {
+		if err := dw.Flush(); err != nil {
+			return err
+		}
+		cp.Offset = dw.Offset()
+		cp.Elapsed = time.Since(start)
+		if opts.Progress == nil {
+			return nil
+		}
+		return opts.Progress(cp)
+	}
+	pending := 0
+	for sc.Next() {
+		row, err := exportRow(ctx, client, tableName, sc.Row(), versions, opts.Attributes)
+		if err != nil {
+			return err
+		}
+		if err := dw.Write(row); err != nil {
+			return err
+		}
+		cp.LastRow = row.Row
+		cp.Rows++
+		cp.Cells += int64(len(row.Cells))
+		if pending++; pending == batch {
+			pending = 0
+			if err := report(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	cp.Done = true
+	return report()
+}
+
+// familyVersions return the number of versions to read per family, those
+// above one only.
+func familyVersions(ctx context.Context, client *hbase.HClient, tableName string, maxVersions int32) (map[string]int32, error) {
+	if maxVersions == 1 {
+		return nil, nil
+	}
+	families, err := client.GetColumnDescriptorsContext(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]int32, len(families))
+	for name, f := range families {
+		n := f.MaxVersions
+		if maxVersions > 0 && maxVersions < n {
+			n = maxVersions
+		}
+		if n > 1 {
+			versions[strings.TrimSuffix(name, ":")] = n
+		}
+	}
+	return versions, nil
+}
+
+// exportRow return the cells of result, with the older versions of the
+// columns of the families in versions.
+func exportRow(ctx context.Context, client *hbase.HClient, tableName string, result *Hbase.TRowResult, versions map[string]int32, attributes map[string]string) (*Row, error) {
+	row := &Row{Row: append([]byte(nil), result.Row...)}
+	columns := make([]string, 0, len(result.Columns))
+	for column := range result.Columns {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		family, _, _ := strings.Cut(column, ":")
+		n := versions[family]
+		if n <= 1 {
+			c := result.Columns[column]
+			row.Cells = append(row.Cells, Cell{Column: []byte(column), Timestamp: c.Timestamp, Value: c.Value})
+			continue
+		}
+		cells, err := client.GetVerContext(ctx, tableName, result.Row, column, n, attributes)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cells {
+			row.Cells = append(row.Cells, Cell{Column: []byte(column), Timestamp: c.Timestamp, Value: c.Value})
+		}
+	}
+	sort.SliceStable(row.Cells, func(i, j int) bool {
+		a, b := row.Cells[i], row.Cells[j]
+		if c := bytes.Compare(a.Column, b.Column); c != 0 {
+			return c < 0
+		}
+		return a.Timestamp > b.Timestamp
+	})
+	return row, nil
+}
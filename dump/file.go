@@ -0,0 +1,150 @@
+package dump
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/J-J-J/hbase"
+)
+
+// ExportFile exports tableName to the file path. It saves the checkpoint
+// of every batch in path+".checkpoint", syncing the dump first, and removes
+// it once done. When that file exists, the export continues from it: the
+// dump is truncated to the checkpoint and the options keep the format it
+// was started with. opts.Resume is ignored.
+func ExportFile(client *hbase.HClient, tableName, path string, opts ExportOptions) error {
+	return ExportFileContext(context.Background(), client, tableName, path, opts)
+}
+
+// ExportFileContext is ExportFile with a context.
+func ExportFileContext(ctx context.Context, client *hbase.HClient, tableName, path string, opts ExportOptions) error {
+	cpPath := path + ".checkpoint"
+	cp, err := loadCheckpoint(cpPath)
+	if err != nil {
+		return err
+	}
+	var f *os.File
+	if cp != nil {
+		if f, err = os.OpenFile(path, os.O_WRONLY, 0); err != nil {
+			return err
+		}
+		if err = f.Truncate(cp.Offset); err == nil {
+			_, err = f.Seek(cp.Offset, io.SeekStart)
+		}
+		if err != nil {
+			f.Close()
+			return err
+		}
+	} else if f, err = os.Create(path); err != nil {
+		return err
+	}
+	defer f.Close()
+
+	opts.Resume = cp
+	progress := opts.Progress
+	opts.Progress = func(cp Checkpoint) error {
+		if err := f.Sync(); err != nil {
+			return err
+		}
+		if !cp.Done {
+			if err := saveCheckpoint(cpPath, &cp); err != nil {
+				return err
+			}
+		}
+		if progress != nil {
+			return progress(cp)
+		}
+		return nil
+	}
+	if err := ExportContext(ctx, client, tableName, f, opts); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return removeCheckpoint(cpPath)
+}
+
+// ImportFile imports the dump in the file path into tableName. It saves
+// the checkpoint of every batch in path+".import.checkpoint" and removes
+// it once done. When that file exists, the import continues from it.
+// opts.Resume is ignored.
+func ImportFile(client *hbase.HClient, tableName, path string, opts ImportOptions) error {
+	return ImportFileContext(context.Background(), client, tableName, path, opts)
+}
+
+// ImportFileContext is ImportFile with a context.
+func ImportFileContext(ctx context.Context, client *hbase.HClient, tableName, path string, opts ImportOptions) error {
+	cpPath := path + ".import.checkpoint"
+	cp, err := loadCheckpoint(cpPath)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if cp != nil {
+		if _, err := f.Seek(cp.Offset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	opts.Resume = cp
+	progress := opts.Progress
+	opts.Progress = func(cp Checkpoint) error {
+		if !cp.Done {
+			if err := saveCheckpoint(cpPath, &cp); err != nil {
+				return err
+			}
+		}
+		if progress != nil {
+			return progress(cp)
+		}
+		return nil
+	}
+	if err := ImportContext(ctx, client, tableName, f, opts); err != nil {
+		return err
+	}
+	return removeCheckpoint(cpPath)
+}
+
+// loadCheckpoint reads the checkpoint saved in path, nil when there is none.
+func loadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, errors.New("dump: invalid checkpoint " + path + ": " + err.Error())
+	}
+	return cp, nil
+}
+
+// saveCheckpoint replaces the checkpoint saved in path, atomically.
+func saveCheckpoint(path string, cp *Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func removeCheckpoint(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package dump
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/J-J-J/hbase"
+	"github.com/J-J-J/hbase/Hbase"
+)
+
+// ImportOptions are the options of Import.
+type ImportOptions struct {
+	// StartRow and StopRow bound the rows imported, StopRow excluded, an
+	// empty bound not limiting.
+	StartRow []byte
+	StopRow  []byte
+	// Columns selects families or family:qualifier columns, all when empty.
+	Columns []string
+	// BatchRows is the number of rows written per batch and between two
+	// calls to Progress, DefaultBatchRows when <= 0.
+	BatchRows int
+	// Progress is called after every batch of rows written, and once more
+	// at the end with cp.Done set. An error stops the import.
+	Progress func(cp Checkpoint) error
+	// Resume continues an import from a checkpoint, the reader being
+	// positioned at its Offset.
+	Resume *Checkpoint
+	// Attributes are passed to MutateRowsTs.
+	Attributes map[string]string
+}
+
+// Import writes the rows of the dump r to tableName, every cell with its
+// original timestamp. The cells of a batch are grouped by timestamp, each
+// group being written with one MutateRowsTs. Rows and columns out of the
+// selection of opts are skipped and counted neither in Rows nor in Cells.
+func Import(client *hbase.HClient, tableName string, r io.Reader, opts ImportOptions) error {
+	return ImportContext(context.Background(), client, tableName, r, opts)
+}
+
+// ImportContext is Import with a context.
+func ImportContext(ctx context.Context, client *hbase.HClient, tableName string, r io.Reader, opts ImportOptions) error {
+	batch := opts.BatchRows
+	if batch <= 0 {
+		batch = DefaultBatchRows
+	}
+	var dr *Reader
+	var cp Checkpoint
+	if opts.Resume != nil {
+		cp = *opts.Resume
+		if cp.Done {
+			return nil
+		}
+		dr = newReaderAt(r, cp.Format, cp.Offset)
+	} else {
+		var err error
+		if dr, err = NewReader(r); err != nil {
+			return err
+		}
+		cp.Format = dr.Format()
+	}
+	start := time.Now().Add(-cp.Elapsed)
+
+	var rows []*Row
+	flush := func(done bool) error {
+		if err := writeRows(ctx, client, tableName, rows, opts.Attributes); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			cp.Rows++
+			cp.Cells += int64(len(row.Cells))
+		}
+		if len(rows) > 0 {
+			cp.LastRow = rows[len(rows)-1].Row
+		}
+		rows = rows[:0]
+		cp.Offset = dr.Offset()
+		cp.Elapsed = time.Since(start)
+		cp.Done = done
+		if opts.Progress == nil {
+			return nil
+		}
+		return opts.Progress(cp)
+	}
+	for {
+		row, err := dr.Read()
+		if err == io.EOF {
+			return flush(true)
+		}
+		if err != nil {
+			return err
+		}
+		if row = selectRow(row, &opts); row == nil {
+			continue
+		}
+		rows = append(rows, row)
+		if len(rows) == batch {
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// selectRow return row with the cells selected by opts, nil when none.
+func selectRow(row *Row, opts *ImportOptions) *Row {
+	if bytes.Compare(row.Row, opts.StartRow) < 0 || (len(opts.StopRow) > 0 && bytes.Compare(row.Row, opts.StopRow) >= 0) {
+		return nil
+	}
+	if len(opts.Columns) == 0 {
+		if len(row.Cells) == 0 {
+			return nil
+		}
+		return row
+	}
+	cells := row.Cells[:0:0]
+	for _, c := range row.Cells {
+		if selected(string(c.Column), opts.Columns) {
+			cells = append(cells, c)
+		}
+	}
+	if len(cells) == 0 {
+		return nil
+	}
+	return &Row{Row: row.Row, Cells: cells}
+}
+
+// selected reports whether column is one of columns or of their families.
+func selected(column string, columns []string) bool {
+	family, _, _ := strings.Cut(column, ":")
+	for _, c := range columns {
+		if c == column || strings.TrimSuffix(c, ":") == family {
+			return true
+		}
+	}
+	return false
+}
+
+// writeRows writes the cells of rows, with one MutateRowsTs per timestamp.
+func writeRows(ctx context.Context, client *hbase.HClient, tableName string, rows []*Row, attributes map[string]string) error {
+	byTs := make(map[int64][]*Hbase.BatchMutation)
+	for _, row := range rows {
+		// the mutations of row per timestamp
+		mutations := make(map[int64]*Hbase.BatchMutation)
+		for _, c := range row.Cells {
+			bm := mutations[c.Timestamp]
+			if bm == nil {
+				bm = &Hbase.BatchMutation{Row: row.Row}
+				mutations[c.Timestamp] = bm
+				byTs[c.Timestamp] = append(byTs[c.Timestamp], bm)
+			}
+			bm.Mutations = append(bm.Mutations, &Hbase.Mutation{Column: c.Column, Value: c.Value, WriteToWAL: true})
+		}
+	}
+	timestamps := make([]int64, 0, len(byTs))
+	for ts := range byTs {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	for _, ts := range timestamps {
+		if err := client.MutateRowsTsContext(ctx, tableName, byTs[ts], ts, attributes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,15 +2,79 @@ package hbase
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
 
 	"github.com/J-J-J/hbase/Hbase"
+	"github.com/J-J-J/hbase/thrift"
 )
 
+// Kinds of errors, matched with errors.Is against the errors of the client
+// calls:
+//
+//	if errors.Is(err, hbase.ErrTableNotFound) {
+//		...
+//	}
+//
+// The exceptions an *Error carries are reached with errors.As, as
+// *Hbase.IOError, *Hbase.IllegalArgument, *Hbase.AlreadyExists,
+// thrift.TApplicationException or thrift.TTransportException.
+var (
+	// ErrTableNotFound is an IOError for a TableNotFoundException.
+	ErrTableNotFound = errors.New("hbase: table not found")
+	// ErrTableExists is the AlreadyExists of CreateTable.
+	ErrTableExists = errors.New("hbase: table already exists")
+	// ErrIllegalArgument is any IllegalArgument.
+	ErrIllegalArgument = errors.New("hbase: illegal argument")
+	// ErrScannerNotFound is an unknown scanner id, or a scanner expired on
+	// the region server.
+	ErrScannerNotFound = errors.New("hbase: scanner not found")
+	// ErrTransportClosed is a connection closed or reset by either side.
+	ErrTransportClosed = errors.New("hbase: transport closed")
+	// ErrTimeout is a call that ran out of time, on a socket deadline or a
+	// context deadline.
+	ErrTimeout = errors.New("hbase: timeout")
+	// ErrApplication is a TApplicationException, the gateway failing to
+	// process a call, an unknown method for example.
+	ErrApplication = errors.New("hbase: thrift application exception")
+)
+
+// scannerLostExceptions name the server exceptions, found in IOError
+// messages, ending a scanner.
+var scannerLostExceptions = []string{
+	"UnknownScannerException",
+	"ScannerTimeoutException",
+	"LeaseException",
+	"OutOfOrderScannerNextException",
+}
+
+// retryableExceptions name the server exceptions, found in IOError
+// messages, after which the same call may succeed later, once a region is
+// moved, opened or less busy.
+var retryableExceptions = []string{
+	"NotServingRegionException",
+	"RegionTooBusyException",
+	"RegionMovedException",
+	"RegionOpeningException",
+	"RegionServerStoppedException",
+	"ServerNotRunningYetException",
+	"ServerTooBusyException",
+	"PleaseHoldException",
+	"CallQueueTooBigException",
+	"CallTimeoutException",
+	"NoServerForRegionException",
+	"SocketTimeoutException",
+	"ConnectException",
+}
+
 // Error return standard Hbase Error
 type Error struct {
-	IOErr  *Hbase.IOError         // IOError
-	ArgErr *Hbase.IllegalArgument // IllegalArgument
-	Err    error                  // error
+	IOErr    *Hbase.IOError         // IOError
+	ArgErr   *Hbase.IllegalArgument // IllegalArgument
+	ExistErr *Hbase.AlreadyExists   // AlreadyExists, from CreateTable
+	Err      error                  // error
 
 }
 
@@ -38,6 +102,11 @@ func (e *Error) String() string {
 		b.WriteString(e.ArgErr.Message)
 		b.WriteString(";")
 	}
+	if e.ExistErr != nil {
+		b.WriteString("AlreadyExists:")
+		b.WriteString(e.ExistErr.Message)
+		b.WriteString(";")
+	}
 	if e.Err != nil {
 		b.WriteString("Error:")
 		b.WriteString(e.Err.Error())
@@ -51,6 +120,110 @@ func (e *Error) Error() string {
 	return e.String()
 }
 
+// Unwrap return the exceptions and the error e carries.
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if e.IOErr != nil {
+		errs = append(errs, e.IOErr)
+	}
+	if e.ArgErr != nil {
+		errs = append(errs, e.ArgErr)
+	}
+	if e.ExistErr != nil {
+		errs = append(errs, e.ExistErr)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// Is reports whether e is of the kind target, one of the Err variables of
+// this package.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrTableNotFound:
+		return e.IOErr != nil && strings.Contains(e.IOErr.Message, "TableNotFoundException")
+	case ErrTableExists:
+		return e.ExistErr != nil
+	case ErrIllegalArgument:
+		return e.ArgErr != nil
+	case ErrScannerNotFound:
+		if e.ArgErr != nil {
+			return strings.Contains(strings.ToLower(e.ArgErr.Message), "scanner")
+		}
+		return e.IOErr != nil && containsAny(e.IOErr.Message, scannerLostExceptions)
+	case ErrTransportClosed:
+		return e.Err != nil && isTransportClosed(e.Err)
+	case ErrTimeout:
+		return e.Err != nil && isTimeout(e.Err)
+	case ErrApplication:
+		_, app := e.Err.(thrift.TApplicationException)
+		return app
+	}
+	return false
+}
+
+func containsAny(s string, names []string) bool {
+	for _, name := range names {
+		if strings.Contains(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransportClosed reports whether err is a connection closed or reset.
+// Transport exceptions only keep the message of the socket error.
+func isTransportClosed(err error) bool {
+	if errors.Is(err, net.ErrClosed) {
+		return true
+	}
+	if e, ok := err.(thrift.TTransportException); ok {
+		switch e.TypeID() {
+		case thrift.NOT_OPEN, thrift.END_OF_FILE:
+			return true
+		}
+	}
+	return containsAny(err.Error(), []string{
+		"use of closed network connection",
+		"connection reset by peer",
+		"broken pipe",
+		"Remote side has closed",
+		"EOF",
+	})
+}
+
+// isTimeout reports whether err is a socket or context deadline.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	if e, ok := err.(thrift.TTransportException); ok && e.TypeID() == thrift.TIMED_OUT {
+		return true
+	}
+	return strings.Contains(err.Error(), "i/o timeout")
+}
+
+// IsRetryable reports whether the call failing with err may succeed if
+// tried again: on a transport failure, the connection being reopened, or on
+// a server exception of a region moving, opening or too busy. Errors of the
+// caller's context are not retryable, nor are the calls the server refused.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var e *Error
+	if errors.As(err, &e) && e.IOErr != nil {
+		return containsAny(e.IOErr.Message, retryableExceptions)
+	}
+	return isConnError(err)
+}
+
 func checkError(io *Hbase.IOError, err error) error {
 	if io != nil || err != nil {
 		return newError(io, nil, err)
@@ -64,3 +237,19 @@ func checkHbaseArgError(io *Hbase.IOError, arg *Hbase.IllegalArgument, err error
 	}
 	return nil
 }
+
+// isConnError reports whether err left the connection in an unknown state,
+// so the client must not be reused without reopening it.
+func isConnError(err error) bool {
+	switch e := err.(type) {
+	case *Error:
+		if e == nil || e.Err == nil {
+			return false
+		}
+		_, app := e.Err.(thrift.TApplicationException)
+		return !app
+	case thrift.TTransportException, thrift.TProtocolException:
+		return true
+	}
+	return false
+}
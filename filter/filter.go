@@ -0,0 +1,528 @@
+// Package filter builds filter strings of the HBase filter language, the
+// language of TScan.FilterString:
+//
+//	f := filter.And(
+//		filter.Prefix([]byte("user#")),
+//		filter.SingleColumnValue([]byte("cf"), []byte("state"), filter.Equal, filter.Binary([]byte("active"))),
+//	)
+//	scan := &hbase.TScan{FilterString: f.String()}
+//
+// Arguments are quoted and escaped as the gateway parses them, binary
+// values included.
+package filter
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// Filter is a filter of the HBase filter language.
+type Filter interface {
+	// String return the filter string.
+	String() string
+	// precedence orders the operators, higher binds tighter.
+	precedence() int
+}
+
+// operator precedences
+const (
+	precOr = iota + 1
+	precAnd
+	precUnary
+)
+
+// CompareOp is a comparison operator of the compare filters.
+type CompareOp string
+
+// compare operators
+const (
+	Less           CompareOp = "<"
+	LessOrEqual    CompareOp = "<="
+	Equal          CompareOp = "="
+	NotEqual       CompareOp = "!="
+	GreaterOrEqual CompareOp = ">="
+	Greater        CompareOp = ">"
+)
+
+// Comparator is what a compare filter compares cells against.
+type Comparator struct {
+	Type  string // binary, binaryprefix, regexstring or substring
+	Value []byte
+}
+
+// comparator types
+const (
+	BinaryType       = "binary"
+	BinaryPrefixType = "binaryprefix"
+	RegexStringType  = "regexstring"
+	SubstringType    = "substring"
+)
+
+// Binary compares lexicographically with value.
+func Binary(value []byte) Comparator {
+	return Comparator{Type: BinaryType, Value: value}
+}
+
+// BinaryPrefix compares lexicographically with value, up to its length.
+func BinaryPrefix(value []byte) Comparator {
+	return Comparator{Type: BinaryPrefixType, Value: value}
+}
+
+// RegexString matches the java regular expression expr. The server only
+// accepts it with Equal and NotEqual.
+func RegexString(expr string) Comparator {
+	return Comparator{Type: RegexStringType, Value: []byte(expr)}
+}
+
+// Substring matches values containing s, case insensitive. The server only
+// accepts it with Equal and NotEqual.
+func Substring(s string) Comparator {
+	return Comparator{Type: SubstringType, Value: []byte(s)}
+}
+
+func (c Comparator) String() string {
+	return quote(append([]byte(c.Type+":"), c.Value...))
+}
+
+// quote return b as a string literal of the filter language, in which a
+// quote is escaped by doubling it and any other byte stands for itself.
+func quote(b []byte) string {
+	var buf bytes.Buffer
+	buf.Grow(len(b) + 2)
+	buf.WriteByte('\'')
+	for _, c := range b {
+		if c == '\'' {
+			buf.WriteByte('\'')
+		}
+		buf.WriteByte(c)
+	}
+	buf.WriteByte('\'')
+	return buf.String()
+}
+
+// call renders name(args...).
+func call(name string, args ...string) string {
+	var buf bytes.Buffer
+	buf.WriteString(name)
+	buf.WriteByte('(')
+	for i, arg := range args {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(arg)
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+func formatInt(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+type simple struct{}
+
+func (simple) precedence() int { return precUnary }
+
+// PrefixFilter keeps the rows whose key starts with Prefix.
+type PrefixFilter struct {
+	simple
+	Prefix []byte
+}
+
+// Prefix return a PrefixFilter.
+func Prefix(prefix []byte) *PrefixFilter {
+	return &PrefixFilter{Prefix: prefix}
+}
+
+func (f *PrefixFilter) String() string {
+	return call("PrefixFilter", quote(f.Prefix))
+}
+
+// ColumnPrefixFilter keeps the columns whose qualifier starts with Prefix.
+type ColumnPrefixFilter struct {
+	simple
+	Prefix []byte
+}
+
+// ColumnPrefix return a ColumnPrefixFilter.
+func ColumnPrefix(prefix []byte) *ColumnPrefixFilter {
+	return &ColumnPrefixFilter{Prefix: prefix}
+}
+
+func (f *ColumnPrefixFilter) String() string {
+	return call("ColumnPrefixFilter", quote(f.Prefix))
+}
+
+// MultipleColumnPrefixFilter keeps the columns whose qualifier starts with
+// any of Prefixes.
+type MultipleColumnPrefixFilter struct {
+	simple
+	Prefixes [][]byte
+}
+
+// MultipleColumnPrefix return a MultipleColumnPrefixFilter.
+func MultipleColumnPrefix(prefixes ...[]byte) *MultipleColumnPrefixFilter {
+	return &MultipleColumnPrefixFilter{Prefixes: prefixes}
+}
+
+func (f *MultipleColumnPrefixFilter) String() string {
+	args := make([]string, len(f.Prefixes))
+	for i, p := range f.Prefixes {
+		args[i] = quote(p)
+	}
+	return call("MultipleColumnPrefixFilter", args...)
+}
+
+// CompareFilter keeps the cells whose row key, family, qualifier or value,
+// depending on Name, compares to Comparator with Op.
+type CompareFilter struct {
+	simple
+	Name       string // RowFilter, FamilyFilter, QualifierFilter or ValueFilter
+	Op         CompareOp
+	Comparator Comparator
+}
+
+// Row return a RowFilter comparing row keys.
+func Row(op CompareOp, c Comparator) *CompareFilter {
+	return &CompareFilter{Name: "RowFilter", Op: op, Comparator: c}
+}
+
+// Family return a FamilyFilter comparing column families.
+func Family(op CompareOp, c Comparator) *CompareFilter {
+	return &CompareFilter{Name: "FamilyFilter", Op: op, Comparator: c}
+}
+
+// Qualifier return a QualifierFilter comparing column qualifiers.
+func Qualifier(op CompareOp, c Comparator) *CompareFilter {
+	return &CompareFilter{Name: "QualifierFilter", Op: op, Comparator: c}
+}
+
+// Value return a ValueFilter comparing cell values.
+func Value(op CompareOp, c Comparator) *CompareFilter {
+	return &CompareFilter{Name: "ValueFilter", Op: op, Comparator: c}
+}
+
+func (f *CompareFilter) String() string {
+	return call(f.Name, string(f.Op), f.Comparator.String())
+}
+
+// SingleColumnValueFilter keeps the rows whose column Family:Qualifier
+// compares to Comparator with Op. Rows without the column are kept unless
+// FilterIfMissing, and only the newest version is tested when
+// LatestVersionOnly. Exclude drops the tested column from the result.
+type SingleColumnValueFilter struct {
+	simple
+	Family            []byte
+	Qualifier         []byte
+	Op                CompareOp
+	Comparator        Comparator
+	FilterIfMissing   bool
+	LatestVersionOnly bool
+	Exclude           bool
+}
+
+// SingleColumnValue return a SingleColumnValueFilter testing the newest
+// version and keeping rows without the column, the server defaults.
+func SingleColumnValue(family, qualifier []byte, op CompareOp, c Comparator) *SingleColumnValueFilter {
+	return &SingleColumnValueFilter{Family: family, Qualifier: qualifier, Op: op, Comparator: c, LatestVersionOnly: true}
+}
+
+// SingleColumnValueExclude is SingleColumnValue dropping the tested column
+// from the result.
+func SingleColumnValueExclude(family, qualifier []byte, op CompareOp, c Comparator) *SingleColumnValueFilter {
+	f := SingleColumnValue(family, qualifier, op, c)
+	f.Exclude = true
+	return f
+}
+
+func (f *SingleColumnValueFilter) String() string {
+	name := "SingleColumnValueFilter"
+	if f.Exclude {
+		name = "SingleColumnValueExcludeFilter"
+	}
+	args := []string{quote(f.Family), quote(f.Qualifier), string(f.Op), f.Comparator.String()}
+	if f.FilterIfMissing || !f.LatestVersionOnly {
+		args = append(args, strconv.FormatBool(f.FilterIfMissing), strconv.FormatBool(f.LatestVersionOnly))
+	}
+	return call(name, args...)
+}
+
+// ColumnValueFilter keeps only the cells of the column Family:Qualifier
+// whose value compares to Comparator with Op, where SingleColumnValueFilter
+// keeps whole rows. It needs HBase 2.
+type ColumnValueFilter struct {
+	simple
+	Family     []byte
+	Qualifier  []byte
+	Op         CompareOp
+	Comparator Comparator
+}
+
+// ColumnValue return a ColumnValueFilter.
+func ColumnValue(family, qualifier []byte, op CompareOp, c Comparator) *ColumnValueFilter {
+	return &ColumnValueFilter{Family: family, Qualifier: qualifier, Op: op, Comparator: c}
+}
+
+func (f *ColumnValueFilter) String() string {
+	return call("ColumnValueFilter", quote(f.Family), quote(f.Qualifier), string(f.Op), f.Comparator.String())
+}
+
+// DependentColumnFilter keeps the cells with the timestamp of a cell of the
+// reference column Family:Qualifier, that cell being tested with Op and
+// Comparator when Op is set. DropDependentColumn drops the reference
+// column from the result.
+type DependentColumnFilter struct {
+	simple
+	Family              []byte
+	Qualifier           []byte
+	DropDependentColumn bool
+	Op                  CompareOp
+	Comparator          Comparator
+}
+
+// DependentColumn return a DependentColumnFilter without value test.
+func DependentColumn(family, qualifier []byte, dropDependentColumn bool) *DependentColumnFilter {
+	return &DependentColumnFilter{Family: family, Qualifier: qualifier, DropDependentColumn: dropDependentColumn}
+}
+
+func (f *DependentColumnFilter) String() string {
+	args := []string{quote(f.Family), quote(f.Qualifier), strconv.FormatBool(f.DropDependentColumn)}
+	if f.Op != "" {
+		args = append(args, string(f.Op), f.Comparator.String())
+	}
+	return call("DependentColumnFilter", args...)
+}
+
+// PageFilter stops after Size rows. Every region applies it on its own, so
+// a scan may still return more rows.
+type PageFilter struct {
+	simple
+	Size int64
+}
+
+// Page return a PageFilter.
+func Page(size int64) *PageFilter {
+	return &PageFilter{Size: size}
+}
+
+func (f *PageFilter) String() string {
+	return call("PageFilter", formatInt(f.Size))
+}
+
+// KeyOnlyFilter strips the values, keeping the keys of the cells.
+type KeyOnlyFilter struct {
+	simple
+	// LenAsVal replaces every value with its length, as a 4 bytes big-endian
+	// integer, instead of an empty value.
+	LenAsVal bool
+}
+
+// KeyOnly return a KeyOnlyFilter.
+func KeyOnly() *KeyOnlyFilter {
+	return &KeyOnlyFilter{}
+}
+
+func (f *KeyOnlyFilter) String() string {
+	if f.LenAsVal {
+		return call("KeyOnlyFilter", "true")
+	}
+	return call("KeyOnlyFilter")
+}
+
+// FirstKeyOnlyFilter keeps the first cell of every row, the cheapest way to
+// list row keys.
+type FirstKeyOnlyFilter struct {
+	simple
+}
+
+// FirstKeyOnly return a FirstKeyOnlyFilter.
+func FirstKeyOnly() *FirstKeyOnlyFilter {
+	return &FirstKeyOnlyFilter{}
+}
+
+func (f *FirstKeyOnlyFilter) String() string {
+	return call("FirstKeyOnlyFilter")
+}
+
+// InclusiveStopFilter ends the scan after the row Stop, included.
+type InclusiveStopFilter struct {
+	simple
+	Stop []byte
+}
+
+// InclusiveStop return an InclusiveStopFilter.
+func InclusiveStop(stop []byte) *InclusiveStopFilter {
+	return &InclusiveStopFilter{Stop: stop}
+}
+
+func (f *InclusiveStopFilter) String() string {
+	return call("InclusiveStopFilter", quote(f.Stop))
+}
+
+// TimestampsFilter keeps the cells written at one of Timestamps.
+type TimestampsFilter struct {
+	simple
+	Timestamps []int64
+}
+
+// Timestamps return a TimestampsFilter.
+func Timestamps(timestamps ...int64) *TimestampsFilter {
+	return &TimestampsFilter{Timestamps: timestamps}
+}
+
+func (f *TimestampsFilter) String() string {
+	args := make([]string, len(f.Timestamps))
+	for i, ts := range f.Timestamps {
+		args[i] = formatInt(ts)
+	}
+	return call("TimestampsFilter", args...)
+}
+
+// ColumnRangeFilter keeps the columns whose qualifier lies between Min and
+// Max, an empty bound being open.
+type ColumnRangeFilter struct {
+	simple
+	Min          []byte
+	MinInclusive bool
+	Max          []byte
+	MaxInclusive bool
+}
+
+// ColumnRange return a ColumnRangeFilter.
+func ColumnRange(min []byte, minInclusive bool, max []byte, maxInclusive bool) *ColumnRangeFilter {
+	return &ColumnRangeFilter{Min: min, MinInclusive: minInclusive, Max: max, MaxInclusive: maxInclusive}
+}
+
+func (f *ColumnRangeFilter) String() string {
+	return call("ColumnRangeFilter", quote(f.Min), strconv.FormatBool(f.MinInclusive), quote(f.Max), strconv.FormatBool(f.MaxInclusive))
+}
+
+// ColumnCountGetFilter keeps the first Limit columns of a row.
+type ColumnCountGetFilter struct {
+	simple
+	Limit int32
+}
+
+// ColumnCountGet return a ColumnCountGetFilter.
+func ColumnCountGet(limit int32) *ColumnCountGetFilter {
+	return &ColumnCountGetFilter{Limit: limit}
+}
+
+func (f *ColumnCountGetFilter) String() string {
+	return call("ColumnCountGetFilter", formatInt(int64(f.Limit)))
+}
+
+// ColumnPaginationFilter keeps Limit columns of every row starting at
+// column Offset.
+type ColumnPaginationFilter struct {
+	simple
+	Limit  int32
+	Offset int32
+}
+
+// ColumnPagination return a ColumnPaginationFilter.
+func ColumnPagination(limit, offset int32) *ColumnPaginationFilter {
+	return &ColumnPaginationFilter{Limit: limit, Offset: offset}
+}
+
+func (f *ColumnPaginationFilter) String() string {
+	return call("ColumnPaginationFilter", formatInt(int64(f.Limit)), formatInt(int64(f.Offset)))
+}
+
+// RawFilter is a filter string used as is, for filters this package does
+// not cover. It is parenthesized when combined.
+type RawFilter string
+
+// Raw return a RawFilter.
+func Raw(expr string) RawFilter {
+	return RawFilter(expr)
+}
+
+func (f RawFilter) precedence() int { return precOr }
+
+func (f RawFilter) String() string {
+	return string(f)
+}
+
+// AndFilter keeps what every one of Filters keeps, it needs at least one.
+type AndFilter struct {
+	Filters []Filter
+}
+
+// And return an AndFilter.
+func And(filters ...Filter) *AndFilter {
+	return &AndFilter{Filters: filters}
+}
+
+func (f *AndFilter) precedence() int { return precAnd }
+
+func (f *AndFilter) String() string {
+	return join(f.Filters, " AND ", precAnd)
+}
+
+// OrFilter keeps what any one of Filters keeps, it needs at least one.
+type OrFilter struct {
+	Filters []Filter
+}
+
+// Or return an OrFilter.
+func Or(filters ...Filter) *OrFilter {
+	return &OrFilter{Filters: filters}
+}
+
+func (f *OrFilter) precedence() int { return precOr }
+
+func (f *OrFilter) String() string {
+	return join(f.Filters, " OR ", precOr)
+}
+
+// SkipFilter drops a whole row when Filter drops any of its cells.
+type SkipFilter struct {
+	Filter Filter
+}
+
+// Skip return a SkipFilter.
+func Skip(f Filter) *SkipFilter {
+	return &SkipFilter{Filter: f}
+}
+
+func (f *SkipFilter) precedence() int { return precUnary }
+
+func (f *SkipFilter) String() string {
+	return "SKIP " + operand(f.Filter, precUnary)
+}
+
+// WhileFilter ends the scan at the first cell Filter drops.
+type WhileFilter struct {
+	Filter Filter
+}
+
+// While return a WhileFilter.
+func While(f Filter) *WhileFilter {
+	return &WhileFilter{Filter: f}
+}
+
+func (f *WhileFilter) precedence() int { return precUnary }
+
+func (f *WhileFilter) String() string {
+	return "WHILE " + operand(f.Filter, precUnary)
+}
+
+// operand renders f, in parentheses when it binds looser than prec.
+func operand(f Filter, prec int) string {
+	if f.precedence() < prec {
+		return "(" + f.String() + ")"
+	}
+	return f.String()
+}
+
+func join(filters []Filter, sep string, prec int) string {
+	var buf bytes.Buffer
+	for i, f := range filters {
+		if i > 0 {
+			buf.WriteString(sep)
+		}
+		buf.WriteString(operand(f, prec))
+	}
+	return buf.String()
+}
@@ -0,0 +1,545 @@
+package filter
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError is an invalid filter string.
+type SyntaxError struct {
+	Offset int // byte offset of the error in the filter string
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return "filter: " + e.Msg + " at offset " + strconv.Itoa(e.Offset)
+}
+
+// Parse parses a filter string into the filters of this package. The number
+// and the types of the arguments of the known filters and the comparators
+// are checked as the server does. Filters of other names, which the server
+// may have registered, are only checked for syntax and parsed as a
+// RawFilter. Rendering the result with String gives back an equivalent
+// filter string.
+func Parse(s string) (Filter, error) {
+	p := &parser{lex: lexer{src: s}}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokEOF {
+		return nil, p.errorf(p.tok, "empty filter string")
+	}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf(p.tok, "unexpected "+p.tok.describe()+", expected AND, OR or the end")
+	}
+	return f, nil
+}
+
+// Validate reports whether s is a valid filter string, see Parse.
+func Validate(s string) error {
+	_, err := Parse(s)
+	return err
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	pos  int
+	text string // name, number or operator as written, unquoted string
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of filter string"
+	case tokString:
+		return "string"
+	case tokLParen:
+		return "'('"
+	case tokRParen:
+		return "')'"
+	case tokComma:
+		return "','"
+	}
+	return "'" + t.text + "'"
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c == '.' || c == '$' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// next scans the token at the current position.
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) && strings.IndexByte(" \t\r\n", l.src[l.pos]) >= 0 {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos == len(l.src) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+	c := l.src[l.pos]
+	switch {
+	case c == '(':
+		l.pos++
+		return token{kind: tokLParen, pos: start}, nil
+	case c == ')':
+		l.pos++
+		return token{kind: tokRParen, pos: start}, nil
+	case c == ',':
+		l.pos++
+		return token{kind: tokComma, pos: start}, nil
+	case c == '\'':
+		return l.quoted()
+	case c == '<' || c == '>' || c == '=' || c == '!':
+		l.pos++
+		if l.pos < len(l.src) && l.src[l.pos] == '=' && c != '=' {
+			l.pos++
+		}
+		op := l.src[start:l.pos]
+		if op == "!" {
+			return token{}, &SyntaxError{Offset: start, Msg: "'!' must be followed by '='"}
+		}
+		return token{kind: tokOp, pos: start, text: op}, nil
+	case c == '-' || '0' <= c && c <= '9':
+		l.pos++
+		for l.pos < len(l.src) && '0' <= l.src[l.pos] && l.src[l.pos] <= '9' {
+			l.pos++
+		}
+		if l.src[start:l.pos] == "-" {
+			return token{}, &SyntaxError{Offset: start, Msg: "'-' must be followed by a digit"}
+		}
+		return token{kind: tokNumber, pos: start, text: l.src[start:l.pos]}, nil
+	case isNameByte(c):
+		for l.pos < len(l.src) && isNameByte(l.src[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokName, pos: start, text: l.src[start:l.pos]}, nil
+	}
+	return token{}, &SyntaxError{Offset: start, Msg: "unexpected character " + strconv.QuoteRune(rune(c))}
+}
+
+// quoted scans a quoted string, in which a doubled quote stands for one.
+func (l *lexer) quoted() (token, error) {
+	start := l.pos
+	l.pos++
+	var buf bytes.Buffer
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		l.pos++
+		if c != '\'' {
+			buf.WriteByte(c)
+			continue
+		}
+		if l.pos < len(l.src) && l.src[l.pos] == '\'' {
+			buf.WriteByte(c)
+			l.pos++
+			continue
+		}
+		return token{kind: tokString, pos: start, text: buf.String()}, nil
+	}
+	return token{}, &SyntaxError{Offset: start, Msg: "unterminated string"}
+}
+
+type parser struct {
+	lex lexer
+	tok token
+}
+
+func (p *parser) next() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(tok token, msg string) error {
+	return &SyntaxError{Offset: tok.pos, Msg: msg}
+}
+
+func (p *parser) keyword(word string) bool {
+	return p.tok.kind == tokName && p.tok.text == word
+}
+
+// parseOr parses filters joined by OR, the loosest operator.
+func (p *parser) parseOr() (Filter, error) {
+	f, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	filters := []Filter{f}
+	for p.keyword("OR") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if f, err = p.parseAnd(); err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if len(filters) == 1 {
+		return f, nil
+	}
+	return &OrFilter{Filters: filters}, nil
+}
+
+// parseAnd parses filters joined by AND.
+func (p *parser) parseAnd() (Filter, error) {
+	f, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	filters := []Filter{f}
+	for p.keyword("AND") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if f, err = p.parseUnary(); err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if len(filters) == 1 {
+		return f, nil
+	}
+	return &AndFilter{Filters: filters}, nil
+}
+
+// parseUnary parses SKIP and WHILE, a parenthesized expression or a filter.
+func (p *parser) parseUnary() (Filter, error) {
+	switch {
+	case p.keyword("SKIP"), p.keyword("WHILE"):
+		word := p.tok.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if word == "SKIP" {
+			return &SkipFilter{Filter: f}, nil
+		}
+		return &WhileFilter{Filter: f}, nil
+	case p.tok.kind == tokLParen:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf(p.tok, "unexpected "+p.tok.describe()+", expected ')'")
+		}
+		return f, p.next()
+	case p.tok.kind == tokName && !p.keyword("AND") && !p.keyword("OR"):
+		return p.parseCall()
+	}
+	return nil, p.errorf(p.tok, "unexpected "+p.tok.describe()+", expected a filter")
+}
+
+// parseCall parses Name(arg, ...) and builds the filter.
+func (p *parser) parseCall() (Filter, error) {
+	name := p.tok
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokLParen {
+		return nil, p.errorf(p.tok, "unexpected "+p.tok.describe()+", expected '(' after "+name.text)
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var args []token
+	for p.tok.kind != tokRParen {
+		if len(args) > 0 {
+			if p.tok.kind != tokComma {
+				return nil, p.errorf(p.tok, "unexpected "+p.tok.describe()+", expected ',' or ')'")
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+		switch p.tok.kind {
+		case tokString, tokNumber, tokOp, tokName:
+			args = append(args, p.tok)
+		default:
+			return nil, p.errorf(p.tok, "unexpected "+p.tok.describe()+", expected an argument")
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	build, ok := builders[name.text]
+	if !ok {
+		f := Raw(p.lex.src[name.pos : p.tok.pos+1])
+		return f, p.next()
+	}
+	c := &argList{name: name, args: args}
+	f := build(c)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return f, p.next()
+}
+
+// argList checks and converts the arguments of one filter, keeping the first
+// error.
+type argList struct {
+	name token
+	args []token
+	err  error
+}
+
+func (c *argList) fail(tok token, msg string) {
+	if c.err == nil {
+		c.err = &SyntaxError{Offset: tok.pos, Msg: c.name.text + ": " + msg}
+	}
+}
+
+// arity checks that the filter got one of counts arguments.
+func (c *argList) arity(counts ...int) bool {
+	for _, n := range counts {
+		if len(c.args) == n {
+			return true
+		}
+	}
+	want := make([]string, len(counts))
+	for i, n := range counts {
+		want[i] = strconv.Itoa(n)
+	}
+	c.fail(c.name, "takes "+strings.Join(want, " or ")+" arguments, got "+strconv.Itoa(len(c.args)))
+	return false
+}
+
+func (c *argList) str(i int) []byte {
+	tok := c.args[i]
+	if tok.kind != tokString {
+		c.fail(tok, "argument "+strconv.Itoa(i+1)+" must be a quoted string, got "+tok.describe())
+		return nil
+	}
+	return []byte(tok.text)
+}
+
+func (c *argList) int64(i int, min, max int64) int64 {
+	tok := c.args[i]
+	if tok.kind != tokNumber {
+		c.fail(tok, "argument "+strconv.Itoa(i+1)+" must be a number, got "+tok.describe())
+		return 0
+	}
+	n, err := strconv.ParseInt(tok.text, 10, 64)
+	if err != nil || n < min || n > max {
+		c.fail(tok, "argument "+strconv.Itoa(i+1)+" is out of range")
+		return 0
+	}
+	return n
+}
+
+func (c *argList) int32(i int) int32 {
+	return int32(c.int64(i, 0, math.MaxInt32))
+}
+
+func (c *argList) bool(i int) bool {
+	tok := c.args[i]
+	if tok.kind == tokName {
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+	}
+	c.fail(tok, "argument "+strconv.Itoa(i+1)+" must be true or false, got "+tok.describe())
+	return false
+}
+
+func (c *argList) op(i int) CompareOp {
+	tok := c.args[i]
+	if tok.kind != tokOp {
+		c.fail(tok, "argument "+strconv.Itoa(i+1)+" must be a comparison operator, got "+tok.describe())
+		return ""
+	}
+	return CompareOp(tok.text)
+}
+
+// comparator parses "type:value", checking that op suits the type.
+func (c *argList) comparator(i int, op CompareOp) Comparator {
+	tok := c.args[i]
+	b := c.str(i)
+	if b == nil && c.err != nil {
+		return Comparator{}
+	}
+	sep := bytes.IndexByte(b, ':')
+	if sep < 0 {
+		c.fail(tok, "argument "+strconv.Itoa(i+1)+" must be a comparator 'type:value'")
+		return Comparator{}
+	}
+	cmp := Comparator{Type: string(b[:sep]), Value: b[sep+1:]}
+	switch cmp.Type {
+	case BinaryType, BinaryPrefixType:
+	case RegexStringType, SubstringType:
+		if op != Equal && op != NotEqual {
+			c.fail(tok, cmp.Type+" comparator only supports = and !=")
+		}
+	default:
+		c.fail(tok, "unknown comparator type "+strconv.Quote(cmp.Type))
+	}
+	return cmp
+}
+
+// builders build the filters known to the server from their arguments.
+var builders = map[string]func(c *argList) Filter{
+	"KeyOnlyFilter": func(c *argList) Filter {
+		if !c.arity(0, 1) {
+			return nil
+		}
+		f := &KeyOnlyFilter{}
+		if len(c.args) == 1 {
+			f.LenAsVal = c.bool(0)
+		}
+		return f
+	},
+	"FirstKeyOnlyFilter": func(c *argList) Filter {
+		c.arity(0)
+		return &FirstKeyOnlyFilter{}
+	},
+	"PrefixFilter": func(c *argList) Filter {
+		if !c.arity(1) {
+			return nil
+		}
+		return &PrefixFilter{Prefix: c.str(0)}
+	},
+	"ColumnPrefixFilter": func(c *argList) Filter {
+		if !c.arity(1) {
+			return nil
+		}
+		return &ColumnPrefixFilter{Prefix: c.str(0)}
+	},
+	"MultipleColumnPrefixFilter": func(c *argList) Filter {
+		if len(c.args) == 0 {
+			c.fail(c.name, "takes at least 1 argument")
+			return nil
+		}
+		f := &MultipleColumnPrefixFilter{}
+		for i := range c.args {
+			f.Prefixes = append(f.Prefixes, c.str(i))
+		}
+		return f
+	},
+	"ColumnCountGetFilter": func(c *argList) Filter {
+		if !c.arity(1) {
+			return nil
+		}
+		return &ColumnCountGetFilter{Limit: c.int32(0)}
+	},
+	"PageFilter": func(c *argList) Filter {
+		if !c.arity(1) {
+			return nil
+		}
+		return &PageFilter{Size: c.int64(0, 0, math.MaxInt64)}
+	},
+	"ColumnPaginationFilter": func(c *argList) Filter {
+		if !c.arity(2) {
+			return nil
+		}
+		return &ColumnPaginationFilter{Limit: c.int32(0), Offset: c.int32(1)}
+	},
+	"InclusiveStopFilter": func(c *argList) Filter {
+		if !c.arity(1) {
+			return nil
+		}
+		return &InclusiveStopFilter{Stop: c.str(0)}
+	},
+	"TimestampsFilter": func(c *argList) Filter {
+		f := &TimestampsFilter{}
+		for i := range c.args {
+			f.Timestamps = append(f.Timestamps, c.int64(i, 0, math.MaxInt64))
+		}
+		return f
+	},
+	"RowFilter":       compareBuilder("RowFilter"),
+	"FamilyFilter":    compareBuilder("FamilyFilter"),
+	"QualifierFilter": compareBuilder("QualifierFilter"),
+	"ValueFilter":     compareBuilder("ValueFilter"),
+	"DependentColumnFilter": func(c *argList) Filter {
+		if !c.arity(2, 3, 5) {
+			return nil
+		}
+		f := &DependentColumnFilter{Family: c.str(0), Qualifier: c.str(1)}
+		if len(c.args) >= 3 {
+			f.DropDependentColumn = c.bool(2)
+		}
+		if len(c.args) == 5 {
+			f.Op = c.op(3)
+			f.Comparator = c.comparator(4, f.Op)
+		}
+		return f
+	},
+	"ColumnValueFilter": func(c *argList) Filter {
+		if !c.arity(4) {
+			return nil
+		}
+		f := &ColumnValueFilter{Family: c.str(0), Qualifier: c.str(1), Op: c.op(2)}
+		f.Comparator = c.comparator(3, f.Op)
+		return f
+	},
+	"SingleColumnValueFilter":        singleColumnValueBuilder(false),
+	"SingleColumnValueExcludeFilter": singleColumnValueBuilder(true),
+	"ColumnRangeFilter": func(c *argList) Filter {
+		if !c.arity(4) {
+			return nil
+		}
+		return &ColumnRangeFilter{Min: c.str(0), MinInclusive: c.bool(1), Max: c.str(2), MaxInclusive: c.bool(3)}
+	},
+}
+
+func compareBuilder(name string) func(c *argList) Filter {
+	return func(c *argList) Filter {
+		if !c.arity(2) {
+			return nil
+		}
+		f := &CompareFilter{Name: name, Op: c.op(0)}
+		f.Comparator = c.comparator(1, f.Op)
+		return f
+	}
+}
+
+func singleColumnValueBuilder(exclude bool) func(c *argList) Filter {
+	return func(c *argList) Filter {
+		if !c.arity(4, 6) {
+			return nil
+		}
+		f := &SingleColumnValueFilter{Family: c.str(0), Qualifier: c.str(1), Op: c.op(2), LatestVersionOnly: true, Exclude: exclude}
+		f.Comparator = c.comparator(3, f.Op)
+		if len(c.args) == 6 {
+			f.FilterIfMissing = c.bool(4)
+			f.LatestVersionOnly = c.bool(5)
+		}
+		return f
+	}
+}
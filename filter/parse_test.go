@@ -0,0 +1,129 @@
+package filter_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/J-J-J/hbase/filter"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	// every byte but the quote stands for itself in a string literal
+	binary := []byte("a'b\x00\xff''(c), AND")
+	prefix := filter.Prefix([]byte("user#"))
+	value := filter.Value(filter.NotEqual, filter.Substring("x"))
+	scvf := filter.SingleColumnValue([]byte("cf"), []byte("q"), filter.Less, filter.Binary(binary))
+	scvf.FilterIfMissing = true
+	scvfAll := filter.SingleColumnValueExclude([]byte("cf"), []byte("q"), filter.GreaterOrEqual, filter.BinaryPrefix([]byte("p")))
+	scvfAll.LatestVersionOnly = false
+	dependent := filter.DependentColumn([]byte("cf"), []byte("ts"), false)
+	dependent.Op = filter.Equal
+	dependent.Comparator = filter.RegexString("^a.*")
+	keyOnly := filter.KeyOnly()
+	keyOnly.LenAsVal = true
+
+	tests := []filter.Filter{
+		prefix,
+		filter.Prefix(binary),
+		filter.ColumnPrefix([]byte("q")),
+		filter.MultipleColumnPrefix([]byte("a"), []byte("b'"), binary),
+		filter.Row(filter.Less, filter.Binary([]byte("r"))),
+		filter.Family(filter.Equal, filter.Binary([]byte("cf"))),
+		filter.Qualifier(filter.LessOrEqual, filter.BinaryPrefix([]byte("q"))),
+		filter.Value(filter.Greater, filter.Binary(binary)),
+		value,
+		filter.SingleColumnValue([]byte("cf"), []byte("q"), filter.Equal, filter.Binary([]byte("v"))),
+		scvf,
+		filter.SingleColumnValueExclude([]byte("cf"), []byte("q"), filter.NotEqual, filter.RegexString("a|b")),
+		scvfAll,
+		filter.ColumnValue([]byte("cf"), []byte("q"), filter.Equal, filter.Binary(binary)),
+		filter.DependentColumn([]byte("cf"), []byte("ts"), true),
+		dependent,
+		filter.Page(0),
+		filter.Page(1 << 40),
+		filter.KeyOnly(),
+		keyOnly,
+		filter.FirstKeyOnly(),
+		filter.InclusiveStop([]byte("z")),
+		filter.Timestamps(),
+		filter.Timestamps(0, 1, 1700000000000),
+		filter.ColumnRange([]byte{}, true, []byte("m"), false),
+		filter.ColumnRange([]byte("a"), false, []byte{}, true),
+		filter.ColumnCountGet(10),
+		filter.ColumnPagination(5, 100),
+		filter.And(prefix, value),
+		filter.Or(prefix, value, filter.FirstKeyOnly()),
+		filter.Or(filter.And(prefix, value), keyOnly),
+		filter.And(filter.Or(prefix, value), filter.Or(keyOnly, scvf)),
+		filter.Skip(value),
+		filter.While(filter.And(prefix, value)),
+		filter.Skip(filter.While(filter.Or(prefix, value))),
+		filter.And(filter.Skip(value), filter.While(prefix)),
+		filter.Raw("CustomFilter('a', 1, true)"),
+		filter.And(filter.Raw("CustomFilter()"), prefix),
+	}
+	for _, f := range tests {
+		s := f.String()
+		got, err := filter.Parse(s)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, f) {
+			t.Errorf("Parse(%q) = %#v, want %#v", s, got, f)
+		}
+		if got.String() != s {
+			t.Errorf("Parse(%q).String() = %q", s, got.String())
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		s      string
+		offset int
+		msg    string // part of the message
+	}{
+		{"", 0, "empty filter string"},
+		{"   ", 3, "empty filter string"},
+		{"PrefixFilter('a'", 16, "expected ',' or ')'"},
+		{"PrefixFilter('a)", 13, "unterminated string"},
+		{"PrefixFilter 'a'", 13, "expected '(' after PrefixFilter"},
+		{"PrefixFilter(1)", 13, "must be a quoted string"},
+		{"PrefixFilter('a', 'b')", 0, "takes 1 arguments, got 2"},
+		{"FirstKeyOnlyFilter(1)", 0, "takes 0 arguments, got 1"},
+		{"PageFilter(-1)", 11, "out of range"},
+		{"ColumnCountGetFilter(2147483648)", 21, "out of range"},
+		{"ValueFilter(<, 'regexstring:a')", 15, "only supports = and !="},
+		{"ValueFilter(=, 'foo:a')", 15, "unknown comparator type"},
+		{"ValueFilter(=, 'a')", 15, "must be a comparator"},
+		{"ValueFilter('=', 'binary:a')", 12, "must be a comparison operator"},
+		{"ColumnValueFilter('cf', 'q', =)", 0, "takes 4 arguments, got 3"},
+		{"ColumnRangeFilter('a', yes, 'b', true)", 23, "must be true or false"},
+		{"TimestampsFilter(1, !2)", 20, "'!' must be followed by '='"},
+		{"TimestampsFilter(1, -)", 20, "'-' must be followed by a digit"},
+		{"TimestampsFilter(1,)", 19, "expected an argument"},
+		{"KeyOnlyFilter() AND", 19, "expected a filter"},
+		{"KeyOnlyFilter() PrefixFilter('a')", 16, "expected AND, OR or the end"},
+		{"AND KeyOnlyFilter()", 0, "expected a filter"},
+		{"SKIP", 4, "expected a filter"},
+		{"(KeyOnlyFilter()", 16, "expected ')'"},
+		{"KeyOnlyFilter())", 15, "expected AND, OR or the end"},
+		{"KeyOnlyFilter() # x", 16, "unexpected character '#'"},
+		// unknown filters are still checked for syntax
+		{"CustomFilter('a' 'b')", 17, "expected ',' or ')'"},
+		{"CustomFilter", 12, "expected '(' after CustomFilter"},
+	}
+	for _, tt := range tests {
+		f, err := filter.Parse(tt.s)
+		serr, ok := err.(*filter.SyntaxError)
+		if !ok {
+			t.Errorf("Parse(%q) = %v, %v, want a SyntaxError", tt.s, f, err)
+			continue
+		}
+		if serr.Offset != tt.offset || !strings.Contains(serr.Msg, tt.msg) {
+			t.Errorf("Parse(%q): got %q at offset %d, want %q at offset %d", tt.s, serr.Msg, serr.Offset, tt.msg, tt.offset)
+		}
+	}
+}
@@ -0,0 +1,567 @@
+package hbasetest
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/J-J-J/hbase/Hbase"
+)
+
+// EnableTable brings a disabled table back on-line.
+func (s *Store) EnableTable(tableName Hbase.Bytes) (*Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, false)
+	if io != nil {
+		return io, nil
+	}
+	if t.enabled {
+		return ioError("org.apache.hadoop.hbase.TableNotDisabledException: " + t.name), nil
+	}
+	t.enabled = true
+	return nil, nil
+}
+
+// DisableTable takes a table off-line, reads and writes then fail.
+func (s *Store) DisableTable(tableName Hbase.Bytes) (*Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, false)
+	if io != nil {
+		return io, nil
+	}
+	if !t.enabled {
+		return ioError("org.apache.hadoop.hbase.TableNotEnabledException: " + t.name), nil
+	}
+	t.enabled = false
+	return nil, nil
+}
+
+// IsTableEnabled reports whether a table is on-line.
+func (s *Store) IsTableEnabled(tableName Hbase.Bytes) (bool, *Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, false)
+	if io != nil {
+		return false, io, nil
+	}
+	return t.enabled, nil, nil
+}
+
+// Compact only checks that the table or region exists.
+func (s *Store) Compact(tableNameOrRegionName Hbase.Bytes) (*Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, io := s.lookupTableOrRegion(tableNameOrRegionName)
+	return io, nil
+}
+
+// MajorCompact only checks that the table or region exists.
+func (s *Store) MajorCompact(tableNameOrRegionName Hbase.Bytes) (*Hbase.IOError, error) {
+	return s.Compact(tableNameOrRegionName)
+}
+
+// lookupTableOrRegion return the table called name, or the table of the
+// region called name.
+func (s *Store) lookupTableOrRegion(name []byte) (*table, *Hbase.IOError) {
+	if t, ok := s.tables[string(name)]; ok {
+		return t, nil
+	}
+	if i := bytes.IndexByte(name, ','); i >= 0 {
+		if t, ok := s.tables[string(name[:i])]; ok {
+			for _, region := range t.regions() {
+				if string(region.Name) == string(name) {
+					return t, nil
+				}
+			}
+		}
+	}
+	return nil, tableNotFound(string(name))
+}
+
+// GetTableNames return the table names in ascending order.
+func (s *Store) GetTableNames() ([]Hbase.Text, *Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.tables))
+	for name := range s.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make([]Hbase.Text, len(names))
+	for i, name := range names {
+		out[i] = Hbase.Text(name)
+	}
+	return out, nil, nil
+}
+
+// GetColumnDescriptors return the families of a table keyed by "family:".
+func (s *Store) GetColumnDescriptors(tableName Hbase.Text) (map[string]*Hbase.ColumnDescriptor, *Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, false)
+	if io != nil {
+		return nil, io, nil
+	}
+	out := make(map[string]*Hbase.ColumnDescriptor, len(t.families))
+	for family, cd := range t.families {
+		c := *cd
+		out[family+":"] = &c
+	}
+	return out, nil, nil
+}
+
+// GetTableRegions return the regions of a table, see Split.
+func (s *Store) GetTableRegions(tableName Hbase.Text) ([]*Hbase.TRegionInfo, *Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, false)
+	if io != nil {
+		return nil, io, nil
+	}
+	return t.regions(), nil, nil
+}
+
+// CreateTable creates an enabled table. Like HBase it needs at least one
+// family and a positive MaxVersions for every family.
+func (s *Store) CreateTable(tableName Hbase.Text, columnFamilies []*Hbase.ColumnDescriptor) (*Hbase.IOError, *Hbase.IllegalArgument, *Hbase.AlreadyExists, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := string(tableName)
+	if name == "" || strings.ContainsAny(name, ",:") {
+		return nil, &Hbase.IllegalArgument{Message: "Illegal table name: " + name}, nil, nil
+	}
+	if _, ok := s.tables[name]; ok {
+		return nil, nil, &Hbase.AlreadyExists{Message: name}, nil
+	}
+	if len(columnFamilies) == 0 {
+		return nil, &Hbase.IllegalArgument{Message: "Table should have at least one column family."}, nil, nil
+	}
+	families := make(map[string]*Hbase.ColumnDescriptor, len(columnFamilies))
+	for _, cd := range columnFamilies {
+		family := strings.TrimSuffix(string(cd.Name), ":")
+		switch {
+		case family == "" || strings.Contains(family, ":"):
+			return nil, &Hbase.IllegalArgument{Message: "Illegal column family name: " + string(cd.Name)}, nil, nil
+		case families[family] != nil:
+			return nil, &Hbase.IllegalArgument{Message: "Family '" + family + "' is specified more than once"}, nil, nil
+		case cd.MaxVersions <= 0:
+			return nil, &Hbase.IllegalArgument{Message: "Maximum versions must be positive"}, nil, nil
+		}
+		c := *cd
+		c.Name = Hbase.Text(family + ":")
+		families[family] = &c
+	}
+	s.tables[name] = &table{
+		name:     name,
+		id:       s.timestamp(),
+		enabled:  true,
+		families: families,
+		rows:     make(map[string]map[string][]cell),
+	}
+	return nil, nil, nil, nil
+}
+
+// DeleteTable drops a disabled table.
+func (s *Store) DeleteTable(tableName Hbase.Text) (*Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, false)
+	if io != nil {
+		return io, nil
+	}
+	if t.enabled {
+		return ioError("org.apache.hadoop.hbase.TableNotDisabledException: " + t.name), nil
+	}
+	delete(s.tables, t.name)
+	return nil, nil
+}
+
+// Get return the newest cell of column, or of every column of a family.
+func (s *Store) Get(tableName Hbase.Text, row Hbase.Text, column Hbase.Text, attributes map[string]Hbase.Text) ([]*Hbase.TCell, *Hbase.IOError, error) {
+	return s.getVersions(tableName, row, column, 0, 1)
+}
+
+// GetVer return up to numVersions newest cells of column.
+func (s *Store) GetVer(tableName Hbase.Text, row Hbase.Text, column Hbase.Text, numVersions int32, attributes map[string]Hbase.Text) ([]*Hbase.TCell, *Hbase.IOError, error) {
+	return s.getVersions(tableName, row, column, 0, numVersions)
+}
+
+// GetVerTs return up to numVersions newest cells of column older than
+// timestamp.
+func (s *Store) GetVerTs(tableName Hbase.Text, row Hbase.Text, column Hbase.Text, timestamp int64, numVersions int32, attributes map[string]Hbase.Text) ([]*Hbase.TCell, *Hbase.IOError, error) {
+	return s.getVersions(tableName, row, column, timestamp, numVersions)
+}
+
+func (s *Store) getVersions(tableName, row, columnName Hbase.Text, maxTs int64, numVersions int32) ([]*Hbase.TCell, *Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, true)
+	if io != nil {
+		return nil, io, nil
+	}
+	columns := []column{parseColumn(columnName)}
+	if io := t.checkFamilies(columns); io != nil {
+		return nil, io, nil
+	}
+	if numVersions <= 0 {
+		numVersions = 1
+	}
+	return flatten(t.read(string(row), columns, maxTs, int(numVersions))), nil, nil
+}
+
+// GetRow return the newest cells of a row.
+func (s *Store) GetRow(tableName Hbase.Text, row Hbase.Text, attributes map[string]Hbase.Text) ([]*Hbase.TRowResult, *Hbase.IOError, error) {
+	return s.getRows(tableName, []Hbase.Text{row}, nil, 0)
+}
+
+// GetRowWithColumns return the newest cells of the given columns of a row.
+func (s *Store) GetRowWithColumns(tableName Hbase.Text, row Hbase.Text, columns []Hbase.Text, attributes map[string]Hbase.Text) ([]*Hbase.TRowResult, *Hbase.IOError, error) {
+	return s.getRows(tableName, []Hbase.Text{row}, columns, 0)
+}
+
+// GetRowTs return the newest cells of a row older than timestamp.
+func (s *Store) GetRowTs(tableName Hbase.Text, row Hbase.Text, timestamp int64, attributes map[string]Hbase.Text) ([]*Hbase.TRowResult, *Hbase.IOError, error) {
+	return s.getRows(tableName, []Hbase.Text{row}, nil, timestamp)
+}
+
+// GetRowWithColumnsTs return the newest cells of the given columns of a row
+// older than timestamp.
+func (s *Store) GetRowWithColumnsTs(tableName Hbase.Text, row Hbase.Text, columns []Hbase.Text, timestamp int64, attributes map[string]Hbase.Text) ([]*Hbase.TRowResult, *Hbase.IOError, error) {
+	return s.getRows(tableName, []Hbase.Text{row}, columns, timestamp)
+}
+
+// GetRows is GetRow for several rows, missing rows are left out.
+func (s *Store) GetRows(tableName Hbase.Text, rows []Hbase.Text, attributes map[string]Hbase.Text) ([]*Hbase.TRowResult, *Hbase.IOError, error) {
+	return s.getRows(tableName, rows, nil, 0)
+}
+
+// GetRowsWithColumns is GetRowWithColumns for several rows.
+func (s *Store) GetRowsWithColumns(tableName Hbase.Text, rows []Hbase.Text, columns []Hbase.Text, attributes map[string]Hbase.Text) ([]*Hbase.TRowResult, *Hbase.IOError, error) {
+	return s.getRows(tableName, rows, columns, 0)
+}
+
+// GetRowsTs is GetRowTs for several rows.
+func (s *Store) GetRowsTs(tableName Hbase.Text, rows []Hbase.Text, timestamp int64, attributes map[string]Hbase.Text) ([]*Hbase.TRowResult, *Hbase.IOError, error) {
+	return s.getRows(tableName, rows, nil, timestamp)
+}
+
+// GetRowsWithColumnsTs is GetRowWithColumnsTs for several rows.
+func (s *Store) GetRowsWithColumnsTs(tableName Hbase.Text, rows []Hbase.Text, columns []Hbase.Text, timestamp int64, attributes map[string]Hbase.Text) ([]*Hbase.TRowResult, *Hbase.IOError, error) {
+	return s.getRows(tableName, rows, columns, timestamp)
+}
+
+func (s *Store) getRows(tableName Hbase.Text, rows []Hbase.Text, columns []Hbase.Text, maxTs int64) ([]*Hbase.TRowResult, *Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, true)
+	if io != nil {
+		return nil, io, nil
+	}
+	cols := parseColumns(columns)
+	if io := t.checkFamilies(cols); io != nil {
+		return nil, io, nil
+	}
+	out := []*Hbase.TRowResult{}
+	for _, row := range rows {
+		if result := t.rowResult(string(row), cols, maxTs); result != nil {
+			out = append(out, result)
+		}
+	}
+	return out, nil, nil
+}
+
+// MutateRow applies the deletes, then the puts of mutations to a row.
+func (s *Store) MutateRow(tableName Hbase.Text, row Hbase.Text, mutations []*Hbase.Mutation, attributes map[string]Hbase.Text) (*Hbase.IOError, *Hbase.IllegalArgument, error) {
+	return s.mutate(tableName, []*Hbase.BatchMutation{{Row: row, Mutations: mutations}}, 0, true)
+}
+
+// MutateRowTs is MutateRow writing at timestamp, deletes remove the versions
+// up to timestamp.
+func (s *Store) MutateRowTs(tableName Hbase.Text, row Hbase.Text, mutations []*Hbase.Mutation, timestamp int64, attributes map[string]Hbase.Text) (*Hbase.IOError, *Hbase.IllegalArgument, error) {
+	return s.mutate(tableName, []*Hbase.BatchMutation{{Row: row, Mutations: mutations}}, timestamp, true)
+}
+
+// MutateRows applies the puts of every batch, then the deletes, as the
+// gateway does.
+func (s *Store) MutateRows(tableName Hbase.Text, rowBatches []*Hbase.BatchMutation, attributes map[string]Hbase.Text) (*Hbase.IOError, *Hbase.IllegalArgument, error) {
+	return s.mutate(tableName, rowBatches, 0, false)
+}
+
+// MutateRowsTs is MutateRows writing at timestamp.
+func (s *Store) MutateRowsTs(tableName Hbase.Text, rowBatches []*Hbase.BatchMutation, timestamp int64, attributes map[string]Hbase.Text) (*Hbase.IOError, *Hbase.IllegalArgument, error) {
+	return s.mutate(tableName, rowBatches, timestamp, false)
+}
+
+// mutate validates every mutation before applying any. A zero ts writes at
+// the current time.
+func (s *Store) mutate(tableName Hbase.Text, batches []*Hbase.BatchMutation, ts int64, deletesFirst bool) (*Hbase.IOError, *Hbase.IllegalArgument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, true)
+	if io != nil {
+		return io, nil, nil
+	}
+	for _, b := range batches {
+		for _, m := range b.Mutations {
+			if io := t.checkFamilies([]column{parseColumn(m.Column)}); io != nil {
+				return io, nil, nil
+			}
+		}
+	}
+	if ts == 0 {
+		ts = s.timestamp()
+	}
+	puts := func() {
+		for _, b := range batches {
+			for _, m := range b.Mutations {
+				if !m.IsDelete {
+					t.put(string(b.Row), parseColumn(m.Column).key(), ts, m.Value)
+				}
+			}
+		}
+	}
+	deletes := func() {
+		for _, b := range batches {
+			for _, m := range b.Mutations {
+				if m.IsDelete {
+					t.delete(string(b.Row), []column{parseColumn(m.Column)}, ts)
+				}
+			}
+		}
+	}
+	if deletesFirst {
+		deletes()
+		puts()
+	} else {
+		puts()
+		deletes()
+	}
+	return nil, nil, nil
+}
+
+// AtomicIncrement adds value to an 8 byte big endian counter and return the
+// new value.
+func (s *Store) AtomicIncrement(tableName Hbase.Text, row Hbase.Text, column Hbase.Text, value int64) (int64, *Hbase.IOError, *Hbase.IllegalArgument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, io := s.increment(tableName, row, column, value)
+	return v, io, nil, nil
+}
+
+func (s *Store) increment(tableName, row, columnName Hbase.Text, amount int64) (int64, *Hbase.IOError) {
+	t, io := s.lookup(tableName, true)
+	if io != nil {
+		return 0, io
+	}
+	c := parseColumn(columnName)
+	if io := t.checkFamilies([]column{c}); io != nil {
+		return 0, io
+	}
+	return t.increment(string(row), c, amount, s.timestamp())
+}
+
+// DeleteAll removes every version of column, or of every column of a
+// family.
+func (s *Store) DeleteAll(tableName Hbase.Text, row Hbase.Text, column Hbase.Text, attributes map[string]Hbase.Text) (*Hbase.IOError, error) {
+	return s.deleteColumns(tableName, row, []Hbase.Text{column}, 0)
+}
+
+// DeleteAllTs removes the versions of column up to timestamp.
+func (s *Store) DeleteAllTs(tableName Hbase.Text, row Hbase.Text, column Hbase.Text, timestamp int64, attributes map[string]Hbase.Text) (*Hbase.IOError, error) {
+	return s.deleteColumns(tableName, row, []Hbase.Text{column}, timestamp)
+}
+
+// DeleteAllRow removes a whole row.
+func (s *Store) DeleteAllRow(tableName Hbase.Text, row Hbase.Text, attributes map[string]Hbase.Text) (*Hbase.IOError, error) {
+	return s.deleteColumns(tableName, row, nil, 0)
+}
+
+// DeleteAllRowTs removes the cells of a row up to timestamp.
+func (s *Store) DeleteAllRowTs(tableName Hbase.Text, row Hbase.Text, timestamp int64, attributes map[string]Hbase.Text) (*Hbase.IOError, error) {
+	return s.deleteColumns(tableName, row, nil, timestamp)
+}
+
+func (s *Store) deleteColumns(tableName, row Hbase.Text, columns []Hbase.Text, ts int64) (*Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, true)
+	if io != nil {
+		return io, nil
+	}
+	cols := parseColumns(columns)
+	if io := t.checkFamilies(cols); io != nil {
+		return io, nil
+	}
+	if ts == 0 {
+		ts = s.timestamp()
+	}
+	t.delete(string(row), cols, ts)
+	return nil, nil
+}
+
+// Increment applies a TIncrement.
+func (s *Store) Increment(increment *Hbase.TIncrement) (*Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, io := s.increment(increment.Table, increment.Row, increment.Column, increment.Ammount)
+	return io, nil
+}
+
+// IncrementRows applies increments in order, up to the first failing one.
+func (s *Store) IncrementRows(increments []*Hbase.TIncrement) (*Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, inc := range increments {
+		if _, io := s.increment(inc.Table, inc.Row, inc.Column, inc.Ammount); io != nil {
+			return io, nil
+		}
+	}
+	return nil, nil
+}
+
+// ScannerOpenWithScan opens a scanner from a TScan. Caching is ignored and
+// a filter string fails with an IOError.
+func (s *Store) ScannerOpenWithScan(tableName Hbase.Text, scan *Hbase.TScan, attributes map[string]Hbase.Text) (Hbase.ScannerID, *Hbase.IOError, error) {
+	if scan == nil {
+		scan = Hbase.NewTScan()
+	}
+	if len(scan.FilterString) > 0 {
+		return 0, ioError("hbasetest: filter strings are not supported"), nil
+	}
+	return s.openScanner(tableName, scan.StartRow, scan.StopRow, nil, scan.Columns, scan.Timestamp)
+}
+
+// ScannerOpen opens a scanner from startRow to the end of the table.
+func (s *Store) ScannerOpen(tableName Hbase.Text, startRow Hbase.Text, columns []Hbase.Text, attributes map[string]Hbase.Text) (Hbase.ScannerID, *Hbase.IOError, error) {
+	return s.openScanner(tableName, startRow, nil, nil, columns, 0)
+}
+
+// ScannerOpenWithStop opens a scanner from startRow up to, not including,
+// stopRow.
+func (s *Store) ScannerOpenWithStop(tableName Hbase.Text, startRow Hbase.Text, stopRow Hbase.Text, columns []Hbase.Text, attributes map[string]Hbase.Text) (Hbase.ScannerID, *Hbase.IOError, error) {
+	return s.openScanner(tableName, startRow, stopRow, nil, columns, 0)
+}
+
+// ScannerOpenWithPrefix opens a scanner over the rows starting with
+// startAndPrefix.
+func (s *Store) ScannerOpenWithPrefix(tableName Hbase.Text, startAndPrefix Hbase.Text, columns []Hbase.Text, attributes map[string]Hbase.Text) (Hbase.ScannerID, *Hbase.IOError, error) {
+	return s.openScanner(tableName, startAndPrefix, nil, startAndPrefix, columns, 0)
+}
+
+// ScannerOpenTs is ScannerOpen returning cells older than timestamp.
+func (s *Store) ScannerOpenTs(tableName Hbase.Text, startRow Hbase.Text, columns []Hbase.Text, timestamp int64, attributes map[string]Hbase.Text) (Hbase.ScannerID, *Hbase.IOError, error) {
+	return s.openScanner(tableName, startRow, nil, nil, columns, timestamp)
+}
+
+// ScannerOpenWithStopTs is ScannerOpenWithStop returning cells older than
+// timestamp.
+func (s *Store) ScannerOpenWithStopTs(tableName Hbase.Text, startRow Hbase.Text, stopRow Hbase.Text, columns []Hbase.Text, timestamp int64, attributes map[string]Hbase.Text) (Hbase.ScannerID, *Hbase.IOError, error) {
+	return s.openScanner(tableName, startRow, stopRow, nil, columns, timestamp)
+}
+
+func (s *Store) openScanner(tableName, startRow, stopRow, prefix Hbase.Text, columns []Hbase.Text, maxTs int64) (Hbase.ScannerID, *Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, true)
+	if io != nil {
+		return 0, io, nil
+	}
+	cols := parseColumns(columns)
+	if io := t.checkFamilies(cols); io != nil {
+		return 0, io, nil
+	}
+	var keys []string
+	for _, key := range t.sortedKeys() {
+		if key < string(startRow) || !strings.HasPrefix(key, string(prefix)) {
+			continue
+		}
+		if len(stopRow) > 0 && key >= string(stopRow) {
+			break
+		}
+		keys = append(keys, key)
+	}
+	s.nextScanner++
+	s.scanners[s.nextScanner] = &scanner{table: t, keys: keys, columns: cols, maxTs: maxTs}
+	return s.nextScanner, nil, nil
+}
+
+// ScannerGet return the next row of a scanner, none once it is exhausted.
+func (s *Store) ScannerGet(id Hbase.ScannerID) ([]*Hbase.TRowResult, *Hbase.IOError, *Hbase.IllegalArgument, error) {
+	return s.ScannerGetList(id, 1)
+}
+
+// ScannerGetList return up to nbRows next rows of a scanner.
+func (s *Store) ScannerGetList(id Hbase.ScannerID, nbRows int32) ([]*Hbase.TRowResult, *Hbase.IOError, *Hbase.IllegalArgument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sc, ok := s.scanners[id]
+	if !ok {
+		return nil, nil, &Hbase.IllegalArgument{Message: "scanner ID is invalid"}, nil
+	}
+	out := []*Hbase.TRowResult{}
+	for sc.pos < len(sc.keys) && int32(len(out)) < nbRows {
+		result := sc.table.rowResult(sc.keys[sc.pos], sc.columns, sc.maxTs)
+		sc.pos++
+		if result != nil {
+			out = append(out, result)
+		}
+	}
+	return out, nil, nil, nil
+}
+
+// ScannerClose releases a scanner.
+func (s *Store) ScannerClose(id Hbase.ScannerID) (*Hbase.IOError, *Hbase.IllegalArgument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.scanners[id]; !ok {
+		return nil, &Hbase.IllegalArgument{Message: "scanner ID is invalid"}, nil
+	}
+	delete(s.scanners, id)
+	return nil, nil, nil
+}
+
+// GetRowOrBefore return the newest cells of family in row, or in the
+// closest row before it that has cells in family.
+func (s *Store) GetRowOrBefore(tableName Hbase.Text, row Hbase.Text, family Hbase.Text) ([]*Hbase.TCell, *Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup(tableName, true)
+	if io != nil {
+		return nil, io, nil
+	}
+	c := parseColumn(family)
+	c.qualifier, c.whole = "", true
+	columns := []column{c}
+	if io := t.checkFamilies(columns); io != nil {
+		return nil, io, nil
+	}
+	keys := t.sortedKeys()
+	for i := sort.SearchStrings(keys, string(row)+"\x00") - 1; i >= 0; i-- {
+		if cells := t.read(keys[i], columns, 0, 1); cells != nil {
+			return flatten(cells), nil, nil
+		}
+	}
+	return []*Hbase.TCell{}, nil, nil
+}
+
+// GetRegionInfo return the region holding the row of a meta row key,
+// "table,row,timestamp".
+func (s *Store) GetRegionInfo(row Hbase.Text) (*Hbase.TRegionInfo, *Hbase.IOError, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := bytes.IndexByte(row, ',')
+	if i < 0 {
+		return nil, ioError("hbasetest: not a region row key: " + string(row)), nil
+	}
+	t, io := s.lookup(row[:i], false)
+	if io != nil {
+		return nil, io, nil
+	}
+	rest := row[i+1:]
+	if j := bytes.LastIndexByte(rest, ','); j >= 0 {
+		rest = rest[:j]
+	}
+	return t.regionOf(string(rest)), nil, nil
+}
@@ -0,0 +1,86 @@
+package hbasetest
+
+import (
+	"net"
+
+	"github.com/J-J-J/hbase"
+	"github.com/J-J-J/hbase/Hbase"
+	"github.com/J-J-J/hbase/thrift"
+)
+
+// Server serves a Store as a thrift gateway on a loopback port.
+type Server struct {
+	store  *Store
+	server *thrift.TSimpleServer
+	done   chan struct{}
+}
+
+// NewServer serves store over plain sockets with the binary protocol, the
+// defaults of NewTCPClient.
+func NewServer(store *Store) (*Server, error) {
+	return NewServerFactory(store, thrift.NewTTransportFactory(), thrift.NewTBinaryProtocol(nil, false, true))
+}
+
+// NewServerFactory serves store with the given transport and protocol, for
+// clients using hbase.WithFramedTransport or hbase.WithCompactProtocol.
+func NewServerFactory(store *Store, transportFactory thrift.TTransportFactory, protocolFactory thrift.TProtocolFactory) (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		store:  store,
+		server: thrift.NewTSimpleServerFactory(Hbase.NewHbaseProcessor(store), l, transportFactory, protocolFactory),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		s.server.Serve()
+	}()
+	return s, nil
+}
+
+// Addr return the host:port the server listens on.
+func (s *Server) Addr() string {
+	return s.server.Addr().String()
+}
+
+// Store return the store being served.
+func (s *Server) Store() *Store {
+	return s.store
+}
+
+// NewClient return an open client connected to the server. opts must match
+// the transport and protocol the server was created with.
+func (s *Server) NewClient(opts ...hbase.ClientOption) (*hbase.HClient, error) {
+	client, err := hbase.NewTCPClient(s.Addr(), false, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err = client.Open(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Close stops the server and drops the connections of its clients.
+func (s *Server) Close() error {
+	err := s.server.Stop()
+	<-s.done
+	return err
+}
+
+// NewClient starts a server on a new empty Store and return a client
+// connected to it. Closing the server releases both.
+func NewClient(opts ...hbase.ClientOption) (*hbase.HClient, *Server, error) {
+	s, err := NewServer(NewStore())
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := s.NewClient(opts...)
+	if err != nil {
+		s.Close()
+		return nil, nil, err
+	}
+	return client, s, nil
+}
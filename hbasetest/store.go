@@ -0,0 +1,375 @@
+// Package hbasetest provides an in-memory HBase for tests of code built on
+// hbase.HClient. Store implements Hbase.IHbase on sorted, multi-versioned
+// tables, and Server serves a Store on a loopback port so a real HClient can
+// talk to it without a cluster.
+package hbasetest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/J-J-J/hbase/Hbase"
+)
+
+// cell is one version of a column.
+type cell struct {
+	ts    int64
+	value []byte
+}
+
+// table holds the rows of a table, every column keeps its versions newest
+// first.
+type table struct {
+	name     string
+	id       int64 // region id, the creation time
+	enabled  bool
+	families map[string]*Hbase.ColumnDescriptor // by family name without ':'
+	rows     map[string]map[string][]cell       // row -> "family:qualifier" -> versions
+	splits   []string                           // region boundaries, ascending
+}
+
+// column selects a whole family or a single column of it.
+type column struct {
+	family    string
+	qualifier string
+	whole     bool
+}
+
+// scanner is an open scanner. The row keys in range are fixed when it is
+// opened, their cells are read as the scan goes.
+type scanner struct {
+	table   *table
+	keys    []string
+	pos     int
+	columns []column
+	maxTs   int64
+}
+
+// Store is an in-memory HBase implementing Hbase.IHbase. It is safe for
+// concurrent use.
+//
+// Tables have a single region unless Split, and keep MaxVersions versions
+// per column. Cells written without an explicit timestamp get strictly
+// increasing ones, so two writes within one millisecond keep distinct
+// versions. A delete removes cells at once instead of writing a tombstone:
+// a later put with an older timestamp is visible again. Filter strings and
+// time to live are not supported. Attributes are ignored.
+type Store struct {
+	mu          sync.Mutex
+	tables      map[string]*table
+	scanners    map[Hbase.ScannerID]*scanner
+	nextScanner Hbase.ScannerID
+	now         func() time.Time
+	lastTs      int64
+}
+
+var _ Hbase.IHbase = (*Store)(nil)
+
+// NewStore return an empty store.
+func NewStore() *Store {
+	return &Store{
+		tables:   make(map[string]*table),
+		scanners: make(map[Hbase.ScannerID]*scanner),
+		now:      time.Now,
+	}
+}
+
+// SetClock replaces the clock timestamps are taken from, nil restores
+// time.Now.
+func (s *Store) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now == nil {
+		now = time.Now
+	}
+	s.now = now
+}
+
+// ExpireScanners drops every open scanner, as an expired lease or a gateway
+// restart does. Their ids then fail with an IllegalArgument.
+func (s *Store) ExpireScanners() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scanners = make(map[Hbase.ScannerID]*scanner)
+}
+
+// timestamp return the next server side timestamp in milliseconds.
+func (s *Store) timestamp() int64 {
+	ts := s.now().UnixNano() / int64(time.Millisecond)
+	if ts <= s.lastTs {
+		ts = s.lastTs + 1
+	}
+	s.lastTs = ts
+	return ts
+}
+
+func ioError(msg string) *Hbase.IOError {
+	return &Hbase.IOError{Message: msg}
+}
+
+func tableNotFound(name string) *Hbase.IOError {
+	return ioError("org.apache.hadoop.hbase.TableNotFoundException: " + name)
+}
+
+// lookup return the table called name, which must be enabled when enabled
+// is set.
+func (s *Store) lookup(name []byte, enabled bool) (*table, *Hbase.IOError) {
+	t, ok := s.tables[string(name)]
+	if !ok {
+		return nil, tableNotFound(string(name))
+	}
+	if enabled && !t.enabled {
+		return nil, ioError("org.apache.hadoop.hbase.TableNotEnabledException: " + t.name + " is disabled.")
+	}
+	return t, nil
+}
+
+// parseColumn splits "family:qualifier". Like the gateway it treats
+// "family" and "family:" as the whole family.
+func parseColumn(c []byte) column {
+	i := bytes.IndexByte(c, ':')
+	if i < 0 {
+		return column{family: string(c), whole: true}
+	}
+	if i == len(c)-1 {
+		return column{family: string(c[:i]), whole: true}
+	}
+	return column{family: string(c[:i]), qualifier: string(c[i+1:])}
+}
+
+func parseColumns(cs []Hbase.Text) []column {
+	if len(cs) == 0 {
+		return nil
+	}
+	columns := make([]column, len(cs))
+	for i, c := range cs {
+		columns[i] = parseColumn(c)
+	}
+	return columns
+}
+
+// key return the name a single column is stored under.
+func (c column) key() string {
+	return c.family + ":" + c.qualifier
+}
+
+func (c column) matches(key string) bool {
+	family, qualifier := splitKey(key)
+	return family == c.family && (c.whole || qualifier == c.qualifier)
+}
+
+func splitKey(key string) (family, qualifier string) {
+	i := strings.IndexByte(key, ':')
+	return key[:i], key[i+1:]
+}
+
+// checkFamilies fails when a column names a family the table does not have.
+func (t *table) checkFamilies(columns []column) *Hbase.IOError {
+	for _, c := range columns {
+		if _, ok := t.families[c.family]; !ok {
+			return ioError("org.apache.hadoop.hbase.regionserver.NoSuchColumnFamilyException: Column family " + c.family + " does not exist in table " + t.name)
+		}
+	}
+	return nil
+}
+
+// selected reports whether key is chosen by columns, nil chooses every
+// column.
+func selected(columns []column, key string) bool {
+	if columns == nil {
+		return true
+	}
+	for _, c := range columns {
+		if c.matches(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// read return up to versions newest cells of every selected column of row,
+// only cells older than maxTs when it is not zero.
+func (t *table) read(row string, columns []column, maxTs int64, versions int) map[string][]cell {
+	var out map[string][]cell
+	for key, cells := range t.rows[row] {
+		if !selected(columns, key) {
+			continue
+		}
+		var picked []cell
+		for _, c := range cells {
+			if maxTs != 0 && c.ts >= maxTs {
+				continue
+			}
+			picked = append(picked, c)
+			if len(picked) == versions {
+				break
+			}
+		}
+		if len(picked) == 0 {
+			continue
+		}
+		if out == nil {
+			out = make(map[string][]cell)
+		}
+		out[key] = picked
+	}
+	return out
+}
+
+// rowResult return the newest selected cells of row, nil when there are
+// none.
+func (t *table) rowResult(row string, columns []column, maxTs int64) *Hbase.TRowResult {
+	cells := t.read(row, columns, maxTs, 1)
+	if cells == nil {
+		return nil
+	}
+	result := &Hbase.TRowResult{Row: Hbase.Text(row), Columns: make(map[string]*Hbase.TCell, len(cells))}
+	for key, versions := range cells {
+		result.Columns[key] = toTCell(versions[0])
+	}
+	return result
+}
+
+// flatten return the cells ordered by column, then newest first.
+func flatten(cells map[string][]cell) []*Hbase.TCell {
+	keys := make([]string, 0, len(cells))
+	for key := range cells {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	out := []*Hbase.TCell{}
+	for _, key := range keys {
+		for _, c := range cells[key] {
+			out = append(out, toTCell(c))
+		}
+	}
+	return out
+}
+
+func toTCell(c cell) *Hbase.TCell {
+	return &Hbase.TCell{Value: Hbase.Bytes(append([]byte(nil), c.value...)), Timestamp: c.ts}
+}
+
+// put stores value as the version ts of key, replacing a version with the
+// same timestamp and dropping the ones beyond MaxVersions.
+func (t *table) put(row, key string, ts int64, value []byte) {
+	columns := t.rows[row]
+	if columns == nil {
+		columns = make(map[string][]cell)
+		t.rows[row] = columns
+	}
+	c := cell{ts: ts, value: append([]byte(nil), value...)}
+	versions := columns[key]
+	i := sort.Search(len(versions), func(i int) bool { return versions[i].ts <= ts })
+	if i < len(versions) && versions[i].ts == ts {
+		versions[i] = c
+	} else {
+		versions = append(versions, cell{})
+		copy(versions[i+1:], versions[i:])
+		versions[i] = c
+	}
+	family, _ := splitKey(key)
+	if max := int(t.families[family].MaxVersions); len(versions) > max {
+		versions = versions[:max]
+	}
+	columns[key] = versions
+}
+
+// delete removes the versions up to ts of the selected columns of row.
+func (t *table) delete(row string, columns []column, ts int64) {
+	for key, versions := range t.rows[row] {
+		if !selected(columns, key) {
+			continue
+		}
+		i := sort.Search(len(versions), func(i int) bool { return versions[i].ts <= ts })
+		if i == 0 {
+			delete(t.rows[row], key)
+		} else {
+			t.rows[row][key] = versions[:i]
+		}
+	}
+	if len(t.rows[row]) == 0 {
+		delete(t.rows, row)
+	}
+}
+
+// increment adds amount to the newest version of c, a missing column counts
+// as zero.
+func (t *table) increment(row string, c column, amount, ts int64) (int64, *Hbase.IOError) {
+	var value int64
+	if versions := t.rows[row][c.key()]; len(versions) > 0 {
+		v := versions[0].value
+		if len(v) != 8 {
+			return 0, ioError("org.apache.hadoop.hbase.DoNotRetryIOException: Attempted to increment field that isn't 64 bits wide")
+		}
+		value = int64(binary.BigEndian.Uint64(v))
+	}
+	value += amount
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(value))
+	t.put(row, c.key(), ts, buf)
+	return value, nil
+}
+
+// sortedKeys return the row keys of the table in ascending order.
+func (t *table) sortedKeys() []string {
+	keys := make([]string, 0, len(t.rows))
+	for key := range t.rows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// regions return the regions of the table in key order, they only exist
+// for GetTableRegions and GetRegionInfo.
+func (t *table) regions() []*Hbase.TRegionInfo {
+	bounds := append(append([]string{""}, t.splits...), "")
+	regions := make([]*Hbase.TRegionInfo, len(bounds)-1)
+	for i := range regions {
+		regions[i] = &Hbase.TRegionInfo{
+			StartKey:   Hbase.Text(bounds[i]),
+			EndKey:     Hbase.Text(bounds[i+1]),
+			Id:         t.id,
+			Name:       Hbase.Text(t.name + "," + bounds[i] + "," + strconv.FormatInt(t.id, 10)),
+			Version:    1,
+			ServerName: Hbase.Text("localhost"),
+		}
+	}
+	return regions
+}
+
+// regionOf return the region holding row.
+func (t *table) regionOf(row string) *Hbase.TRegionInfo {
+	return t.regions()[sort.SearchStrings(t.splits, row+"\x00")]
+}
+
+// Split divides a table into regions starting at splitKeys, the first one
+// starting at the empty key. Regions only show in GetTableRegions and
+// GetRegionInfo, for code scanning region by region.
+func (s *Store) Split(tableName string, splitKeys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, io := s.lookup([]byte(tableName), false)
+	if io != nil {
+		return errors.New(io.Message)
+	}
+	splits := make(map[string]bool)
+	for _, key := range append(t.splits, splitKeys...) {
+		if key != "" {
+			splits[key] = true
+		}
+	}
+	t.splits = t.splits[:0]
+	for key := range splits {
+		t.splits = append(t.splits, key)
+	}
+	sort.Strings(t.splits)
+	return nil
+}
@@ -1,44 +1,119 @@
 package hbase
 
 import (
+	"context"
+	"crypto/tls"
 	"net"
+	"sync"
+	"time"
 
 	"github.com/J-J-J/hbase/Hbase"
+	"github.com/J-J-J/hbase/filter"
 	"github.com/J-J-J/hbase/thrift"
 )
 
 const (
 	stateDefault = iota // default close
 	stateOpen           // open
+	stateBroken         // transport dropped after a failed call, reopened on next call
 )
 
-// HClient is a wrap of hbase client
+// HClient is a wrap of hbase client. Calls from several goroutines are
+// serialized on its single connection.
 type HClient struct {
-	addr  string
-	state int
-	Trans thrift.TTransport
-	hbase *Hbase.HbaseClient
+	mu       sync.Mutex // held for the duration of a call
+	addr     string
+	state    int
+	retry    *RetryPolicy
+	validate bool // see WithFilterValidation
+	Trans    thrift.TTransport
+	hbase    *Hbase.HbaseClient
+
+	gw       *gateway // set when dialed by a Balancer
+	balancer *Balancer
+
+	observer Observer           // see WithObserver
+	counter  *countingTransport // bytes of the current attempt, set with observer
+	rows     int                // rows returned by the current attempt
+	scanners sync.Map           // table of the open scanners, kept with observer
 }
 
-// NewTCPClient return a base tcp client instance
-func NewTCPClient(rawaddr string, buffered bool) (client *HClient, err error) {
+// NewTCPClient return a base tcp client instance. buffered wraps the socket
+// in a thrift.TBufferedTransport.
+func NewTCPClient(rawaddr string, buffered bool, opts ...ClientOption) (client *HClient, err error) {
 	tcpAddr, err := net.ResolveTCPAddr("tcp", rawaddr)
 	if err != nil {
 		return
 	}
-	trans := thrift.NewTSocketAddr(tcpAddr)
-	client = &HClient{
-		addr:  tcpAddr.String(),
-		Trans: trans,
+	client = newClient(tcpAddr.String(), thrift.NewTSocketAddr(tcpAddr), newClientOptions(buffered, opts))
+	return
+}
+
+// NewTLSClient return a tcp client speaking TLS. A config without ServerName
+// verifies the host part of rawaddr, and MinVersion defaults to TLS 1.2.
+func NewTLSClient(rawaddr string, config *tls.Config, buffered bool, opts ...ClientOption) (client *HClient, err error) {
+	host, _, err := net.SplitHostPort(rawaddr)
+	if err != nil {
+		return
+	}
+	tcpAddr, err := net.ResolveTCPAddr("tcp", rawaddr)
+	if err != nil {
+		return
+	}
+	if config == nil {
+		config = &tls.Config{}
+	} else {
+		config = config.Clone()
+	}
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS12
+	}
+	client = newClient(tcpAddr.String(), thrift.NewTSSLSocket(tcpAddr, config, 0), newClientOptions(buffered, opts))
+	return
+}
+
+// NewHTTPClient return a client for a thrift gateway running in -http mode,
+// posting every call to url. Buffering and framing options do not apply.
+func NewHTTPClient(url string, opts ...ClientOption) (client *HClient, err error) {
+	o := newClientOptions(false, opts)
+	trans, err := thrift.NewTHttpClientWithClient(url, o.httpClient)
+	if err != nil {
+		return
+	}
+	for key, values := range o.httpHeader {
+		for _, value := range values {
+			trans.SetHeader(key, value)
+		}
 	}
-	protocol := thrift.NewTBinaryProtocol(trans, false, true)
-	client.hbase = Hbase.NewHbaseClientFactory(trans, protocol)
+	for _, cookie := range o.httpCookies {
+		trans.AddCookie(cookie)
+	}
+	if o.basicAuth != nil {
+		trans.SetBasicAuth(o.basicAuth[0], o.basicAuth[1])
+	}
+	o.framed = false
+	client = newClient(url, trans, o)
 	return
 }
 
+// Addr return the address the client connects to.
+func (client *HClient) Addr() string {
+	return client.addr
+}
+
 // Open connection
 func (client *HClient) Open() error {
-	if client.state == stateDefault {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.open()
+}
+
+// open opens the connection, client.mu being held.
+func (client *HClient) open() error {
+	if client.state != stateOpen {
 		if err := client.Trans.Open(); err != nil {
 			return err
 		}
@@ -47,22 +122,137 @@ func (client *HClient) Open() error {
 	return nil
 }
 
-// Close connection
+// isOpen reports whether the connection is open and was not dropped.
+func (client *HClient) isOpen() bool {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.state == stateOpen && client.Trans.IsOpen()
+}
+
+// Close connection. A call in progress on another goroutine completes
+// first.
 func (client *HClient) Close() error {
-	if client.state == stateOpen {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	switch client.state {
+	case stateOpen:
 		if err := client.Trans.Close(); err != nil {
 			return err
 		}
 		client.state = stateDefault
+	case stateBroken:
+		client.state = stateDefault
 	}
 	return nil
 }
 
+// call runs one request/response exchange named method on table under ctx
+// and retries it per the client RetryPolicy when the transport fails. Every
+// attempt is reported to the client Observer.
+func (client *HClient) call(ctx context.Context, method, table string, fn func() error) error {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.gw != nil {
+		client.gw.acquire()
+		defer client.gw.release()
+	}
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		client.resetCounters()
+		sent, err := client.exchange(ctx, fn)
+		if client.observer != nil {
+			client.observe(method, table, attempt, start, err)
+		}
+		if client.gw != nil {
+			client.balancer.report(client.gw, isConnError(err) && ctx.Err() == nil)
+		}
+		if err == nil || !isConnError(err) || ctx.Err() != nil {
+			return err
+		}
+		// a call that never reached the server is safe to replay
+		if !client.retry.allows(attempt) || (sent && !replayable(method)) {
+			return err
+		}
+		if e := sleepContext(ctx, client.retry.backoff(attempt)); e != nil {
+			return err
+		}
+	}
+}
+
+// exchange runs fn once, reopening a dropped transport first. The context
+// deadline becomes the transport deadline, and cancelling ctx moves that
+// deadline to the past so a pending read or write returns at once. A call
+// that fails in the transport may have left half a frame on the wire, so the
+// connection is dropped and reopened by the next call. sent reports whether
+// the request may have reached the server.
+func (client *HClient) exchange(ctx context.Context, fn func() error) (sent bool, err error) {
+	if err = ctx.Err(); err != nil {
+		return false, newError(nil, nil, err)
+	}
+	if client.state == stateBroken {
+		if err = client.open(); err != nil {
+			return false, newError(nil, nil, err)
+		}
+	}
+
+	dt, ok := client.Trans.(thrift.TDeadlineTransport)
+	if !ok || ctx.Done() == nil {
+		err = fn()
+		if isConnError(err) {
+			client.drop()
+		}
+		return true, err
+	}
+
+	deadline, hasDeadline := ctx.Deadline()
+	dt.SetDeadline(deadline)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-ctx.Done():
+			dt.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+	err = fn()
+	close(stop)
+	<-done
+	dt.SetDeadline(time.Time{})
+
+	if isConnError(err) {
+		client.drop()
+		if e := ctx.Err(); e != nil {
+			return true, newError(nil, nil, e)
+		}
+		if hasDeadline && !time.Now().Before(deadline) {
+			return true, newError(nil, nil, context.DeadlineExceeded)
+		}
+	}
+	return true, err
+}
+
+// drop closes a connection whose stream can no longer be trusted.
+func (client *HClient) drop() {
+	if client.state == stateOpen {
+		client.Trans.Close()
+		client.state = stateBroken
+	}
+}
+
 // Brings a table on-line (enables it)
 // Parameters:
 //  - TableName: name of the table
 func (client *HClient) EnableTable(tableName string) error {
-	return checkError(client.hbase.EnableTable(Hbase.Bytes(tableName)))
+	return client.EnableTableContext(context.Background(), tableName)
+}
+
+// EnableTableContext is EnableTable with a context, see HClient.call.
+func (client *HClient) EnableTableContext(ctx context.Context, tableName string) error {
+	return client.call(ctx, "enableTable", tableName, func() error {
+		return checkError(client.hbase.EnableTable(Hbase.Bytes(tableName)))
+	})
 }
 
 // Disables a table (takes it off-line) If it is being served, the master
@@ -70,28 +260,61 @@ func (client *HClient) EnableTable(tableName string) error {
 // Parameters:
 //  - TableName: name of the table
 func (client *HClient) DisableTable(tableName string) (err error) {
-	return checkError(client.hbase.DisableTable(Hbase.Bytes(tableName)))
+	return client.DisableTableContext(context.Background(), tableName)
+}
+
+// DisableTableContext is DisableTable with a context, see HClient.call.
+func (client *HClient) DisableTableContext(ctx context.Context, tableName string) (err error) {
+	return client.call(ctx, "disableTable", tableName, func() error {
+		return checkError(client.hbase.DisableTable(Hbase.Bytes(tableName)))
+	})
 }
 
 // @return true if table is on-line
 // Parameters:
 //  - TableName: name of the table to check
 func (client *HClient) IsTableEnabled(tableName string) (ret bool, err error) {
-	ret, io, e1 := client.hbase.IsTableEnabled(Hbase.Bytes(tableName))
-	err = checkError(io, e1)
+	return client.IsTableEnabledContext(context.Background(), tableName)
+}
+
+// IsTableEnabledContext is IsTableEnabled with a context, see HClient.call.
+func (client *HClient) IsTableEnabledContext(ctx context.Context, tableName string) (ret bool, err error) {
+	err = client.call(ctx, "isTableEnabled", tableName, func() (err error) {
+		enabled, io, e1 := client.hbase.IsTableEnabled(Hbase.Bytes(tableName))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
+
+		ret = enabled
+		return
+	})
 	return
 }
 
 // Parameters:
 //  - TableNameOrRegionName
 func (client *HClient) Compact(tableNameOrRegionName string) (err error) {
-	return checkError(client.hbase.Compact(Hbase.Bytes(tableNameOrRegionName)))
+	return client.CompactContext(context.Background(), tableNameOrRegionName)
+}
+
+// CompactContext is Compact with a context, see HClient.call.
+func (client *HClient) CompactContext(ctx context.Context, tableNameOrRegionName string) (err error) {
+	return client.call(ctx, "compact", tableNameOrRegionName, func() error {
+		return checkError(client.hbase.Compact(Hbase.Bytes(tableNameOrRegionName)))
+	})
 }
 
 // Parameters:
 //  - TableNameOrRegionName
 func (client *HClient) MajorCompact(tableNameOrRegionName string) (err error) {
-	return checkError(client.hbase.MajorCompact(Hbase.Bytes(tableNameOrRegionName)))
+	return client.MajorCompactContext(context.Background(), tableNameOrRegionName)
+}
+
+// MajorCompactContext is MajorCompact with a context, see HClient.call.
+func (client *HClient) MajorCompactContext(ctx context.Context, tableNameOrRegionName string) (err error) {
+	return client.call(ctx, "majorCompact", tableNameOrRegionName, func() error {
+		return checkError(client.hbase.MajorCompact(Hbase.Bytes(tableNameOrRegionName)))
+	})
 }
 
 // List all the column families assoicated with a table.
@@ -99,12 +322,20 @@ func (client *HClient) MajorCompact(tableNameOrRegionName string) (err error) {
 // Parameters:
 //  - TableName: table name
 func (client *HClient) GetTableNames() (tables []string, err error) {
-	ret, io, e1 := client.hbase.GetTableNames()
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetTableNamesContext(context.Background())
+}
 
-	tables = textListToStr(ret)
+// GetTableNamesContext is GetTableNames with a context, see HClient.call.
+func (client *HClient) GetTableNamesContext(ctx context.Context) (tables []string, err error) {
+	err = client.call(ctx, "getTableNames", "", func() (err error) {
+		ret, io, e1 := client.hbase.GetTableNames()
+		if err = checkError(io, e1); err != nil {
+			return
+		}
+
+		tables = textListToStr(ret)
+		return
+	})
 	return
 }
 
@@ -113,11 +344,19 @@ func (client *HClient) GetTableNames() (tables []string, err error) {
 // Parameters:
 //  - TableName: table name
 func (client *HClient) GetColumnDescriptors(tableName string) (columns map[string]*ColumnDescriptor, err error) {
-	ret, io, e1 := client.hbase.GetColumnDescriptors(Hbase.Text(tableName))
-	if err = checkError(io, e1); err != nil {
+	return client.GetColumnDescriptorsContext(context.Background(), tableName)
+}
+
+// GetColumnDescriptorsContext is GetColumnDescriptors with a context, see HClient.call.
+func (client *HClient) GetColumnDescriptorsContext(ctx context.Context, tableName string) (columns map[string]*ColumnDescriptor, err error) {
+	err = client.call(ctx, "getColumnDescriptors", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetColumnDescriptors(Hbase.Text(tableName))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
+		columns = toColMap(ret)
 		return
-	}
-	columns = toColMap(ret)
+	})
 	return
 }
 
@@ -126,12 +365,20 @@ func (client *HClient) GetColumnDescriptors(tableName string) (columns map[strin
 // Parameters:
 //  - TableName: table name
 func (client *HClient) GetTableRegions(tableName string) (regions []*TRegionInfo, err error) {
-	ret, io, e1 := client.hbase.GetTableRegions(Hbase.Text(tableName))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetTableRegionsContext(context.Background(), tableName)
+}
+
+// GetTableRegionsContext is GetTableRegions with a context, see HClient.call.
+func (client *HClient) GetTableRegionsContext(ctx context.Context, tableName string) (regions []*TRegionInfo, err error) {
+	err = client.call(ctx, "getTableRegions", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetTableRegions(Hbase.Text(tableName))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	regions = toRegionList(ret)
+		regions = toRegionList(ret)
+		return
+	})
 	return
 }
 
@@ -141,16 +388,28 @@ func (client *HClient) GetTableRegions(tableName string) (regions []*TRegionInfo
 // values if not explicitly specified.
 // @throws IllegalArgument if an input parameter is invalid
 // @throws AlreadyExists if the table name already exists
+// An existing table sets exists and return an error matching ErrTableExists.
 // Parameters:
 //  - TableName: name of table to create
 //  - ColumnFamilies: list of column family descriptors
 func (client *HClient) CreateTable(tableName string, columnFamilies []*ColumnDescriptor) (exists bool, err error) {
-	columns := toHbaseColList(columnFamilies)
-	io, ia, ex, e1 := client.hbase.CreateTable(Hbase.Text(tableName), columns)
-	if err = checkHbaseArgError(io, ia, e1); err != nil {
+	return client.CreateTableContext(context.Background(), tableName, columnFamilies)
+}
+
+// CreateTableContext is CreateTable with a context, see HClient.call.
+func (client *HClient) CreateTableContext(ctx context.Context, tableName string, columnFamilies []*ColumnDescriptor) (exists bool, err error) {
+	err = client.call(ctx, "createTable", tableName, func() (err error) {
+		columns := toHbaseColList(columnFamilies)
+		io, ia, ex, e1 := client.hbase.CreateTable(Hbase.Text(tableName), columns)
+		if err = checkHbaseArgError(io, ia, e1); err != nil {
+			return
+		}
+		if ex != nil {
+			exists = true
+			err = &Error{ExistErr: ex}
+		}
 		return
-	}
-	exists = (ex != nil)
+	})
 	return
 }
 
@@ -160,7 +419,14 @@ func (client *HClient) CreateTable(tableName string, columnFamilies []*ColumnDes
 // Parameters:
 //  - TableName: name of table to delete
 func (client *HClient) DeleteTable(tableName string) (err error) {
-	return checkError(client.hbase.DeleteTable(Hbase.Text(tableName)))
+	return client.DeleteTableContext(context.Background(), tableName)
+}
+
+// DeleteTableContext is DeleteTable with a context, see HClient.call.
+func (client *HClient) DeleteTableContext(ctx context.Context, tableName string) (err error) {
+	return client.call(ctx, "deleteTable", tableName, func() error {
+		return checkError(client.hbase.DeleteTable(Hbase.Text(tableName)))
+	})
 }
 
 // Get a single TCell for the specified table, row, and column at the
@@ -172,12 +438,21 @@ func (client *HClient) DeleteTable(tableName string) (err error) {
 //  - Column: column name
 //  - Attributes: Get attributes
 func (client *HClient) Get(tableName string, row []byte, column string, attributes map[string]string) (data []*Hbase.TCell, err error) {
-	ret, io, e1 := client.hbase.Get(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetContext(context.Background(), tableName, row, column, attributes)
+}
+
+// GetContext is Get with a context, see HClient.call.
+func (client *HClient) GetContext(ctx context.Context, tableName string, row []byte, column string, attributes map[string]string) (data []*Hbase.TCell, err error) {
+	err = client.call(ctx, "get", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.Get(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	data = ret
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -191,12 +466,21 @@ func (client *HClient) Get(tableName string, row []byte, column string, attribut
 //  - NumVersions: number of versions to retrieve
 //  - Attributes: Get attributes
 func (client *HClient) GetVer(tableName string, row []byte, column string, numVersions int32, attributes map[string]string) (data []*Hbase.TCell, err error) {
-	ret, io, e1 := client.hbase.GetVer(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), numVersions, toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetVerContext(context.Background(), tableName, row, column, numVersions, attributes)
+}
+
+// GetVerContext is GetVer with a context, see HClient.call.
+func (client *HClient) GetVerContext(ctx context.Context, tableName string, row []byte, column string, numVersions int32, attributes map[string]string) (data []*Hbase.TCell, err error) {
+	err = client.call(ctx, "getVer", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetVer(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), numVersions, toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	data = ret
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -212,12 +496,21 @@ func (client *HClient) GetVer(tableName string, row []byte, column string, numVe
 //  - NumVersions: number of versions to retrieve
 //  - Attributes: Get attributes
 func (client *HClient) GetVerTs(tableName string, row []byte, column string, timestamp int64, numVersions int32, attributes map[string]string) (data []*Hbase.TCell, err error) {
-	ret, io, e1 := client.hbase.GetVerTs(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), timestamp, numVersions, toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetVerTsContext(context.Background(), tableName, row, column, timestamp, numVersions, attributes)
+}
 
-	data = ret
+// GetVerTsContext is GetVerTs with a context, see HClient.call.
+func (client *HClient) GetVerTsContext(ctx context.Context, tableName string, row []byte, column string, timestamp int64, numVersions int32, attributes map[string]string) (data []*Hbase.TCell, err error) {
+	err = client.call(ctx, "getVerTs", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetVerTs(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), timestamp, numVersions, toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
+
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -229,12 +522,21 @@ func (client *HClient) GetVerTs(tableName string, row []byte, column string, tim
 //  - Row: row key
 //  - Attributes: Get attributes
 func (client *HClient) GetRow(tableName string, row []byte, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
-	ret, io, e1 := client.hbase.GetRow(Hbase.Text(tableName), Hbase.Text(row), toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetRowContext(context.Background(), tableName, row, attributes)
+}
+
+// GetRowContext is GetRow with a context, see HClient.call.
+func (client *HClient) GetRowContext(ctx context.Context, tableName string, row []byte, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
+	err = client.call(ctx, "getRow", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetRow(Hbase.Text(tableName), Hbase.Text(row), toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	data = ret
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -247,12 +549,21 @@ func (client *HClient) GetRow(tableName string, row []byte, attributes map[strin
 //  - Columns: List of columns to return, null for all columns
 //  - Attributes: Get attributes
 func (client *HClient) GetRowWithColumns(tableName string, row []byte, columns []string, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
-	ret, io, e1 := client.hbase.GetRowWithColumns(Hbase.Text(tableName), Hbase.Text(row), toHbaseTextList(columns), toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetRowWithColumnsContext(context.Background(), tableName, row, columns, attributes)
+}
 
-	data = ret
+// GetRowWithColumnsContext is GetRowWithColumns with a context, see HClient.call.
+func (client *HClient) GetRowWithColumnsContext(ctx context.Context, tableName string, row []byte, columns []string, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
+	err = client.call(ctx, "getRowWithColumns", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetRowWithColumns(Hbase.Text(tableName), Hbase.Text(row), toHbaseTextList(columns), toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
+
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -265,12 +576,21 @@ func (client *HClient) GetRowWithColumns(tableName string, row []byte, columns [
 //  - Timestamp: timestamp
 //  - Attributes: Get attributes
 func (client *HClient) GetRowTs(tableName string, row []byte, timestamp int64, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
-	ret, io, e1 := client.hbase.GetRowTs(Hbase.Text(tableName), Hbase.Text(row), timestamp, toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetRowTsContext(context.Background(), tableName, row, timestamp, attributes)
+}
+
+// GetRowTsContext is GetRowTs with a context, see HClient.call.
+func (client *HClient) GetRowTsContext(ctx context.Context, tableName string, row []byte, timestamp int64, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
+	err = client.call(ctx, "getRowTs", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetRowTs(Hbase.Text(tableName), Hbase.Text(row), timestamp, toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	data = ret
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -284,12 +604,21 @@ func (client *HClient) GetRowTs(tableName string, row []byte, timestamp int64, a
 //  - Timestamp
 //  - Attributes: Get attributes
 func (client *HClient) GetRowWithColumnsTs(tableName string, row []byte, columns []string, timestamp int64, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
-	ret, io, e1 := client.hbase.GetRowWithColumnsTs(Hbase.Text(tableName), Hbase.Text(row), toHbaseTextList(columns), timestamp, toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetRowWithColumnsTsContext(context.Background(), tableName, row, columns, timestamp, attributes)
+}
 
-	data = ret
+// GetRowWithColumnsTsContext is GetRowWithColumnsTs with a context, see HClient.call.
+func (client *HClient) GetRowWithColumnsTsContext(ctx context.Context, tableName string, row []byte, columns []string, timestamp int64, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
+	err = client.call(ctx, "getRowWithColumnsTs", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetRowWithColumnsTs(Hbase.Text(tableName), Hbase.Text(row), toHbaseTextList(columns), timestamp, toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
+
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -301,12 +630,21 @@ func (client *HClient) GetRowWithColumnsTs(tableName string, row []byte, columns
 //  - Rows: row keys
 //  - Attributes: Get attributes
 func (client *HClient) GetRows(tableName string, rows [][]byte, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
-	ret, io, e1 := client.hbase.GetRows(Hbase.Text(tableName), toHbaseTextListFromByte(rows), toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetRowsContext(context.Background(), tableName, rows, attributes)
+}
+
+// GetRowsContext is GetRows with a context, see HClient.call.
+func (client *HClient) GetRowsContext(ctx context.Context, tableName string, rows [][]byte, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
+	err = client.call(ctx, "getRows", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetRows(Hbase.Text(tableName), toHbaseTextListFromByte(rows), toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	data = ret
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -319,16 +657,25 @@ func (client *HClient) GetRows(tableName string, rows [][]byte, attributes map[s
 //  - Columns: List of columns to return, null for all columns
 //  - Attributes: Get attributes
 func (client *HClient) GetRowsWithColumns(tableName string, rows [][]byte, columns []string, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
-	if err = client.Open(); err != nil {
-		return
-	}
+	return client.GetRowsWithColumnsContext(context.Background(), tableName, rows, columns, attributes)
+}
 
-	ret, io, e1 := client.hbase.GetRowsWithColumns(Hbase.Text(tableName), toHbaseTextListFromByte(rows), toHbaseTextList(columns), toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+// GetRowsWithColumnsContext is GetRowsWithColumns with a context, see HClient.call.
+func (client *HClient) GetRowsWithColumnsContext(ctx context.Context, tableName string, rows [][]byte, columns []string, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
+	err = client.call(ctx, "getRowsWithColumns", tableName, func() (err error) {
+		if err = client.open(); err != nil {
+			return
+		}
+
+		ret, io, e1 := client.hbase.GetRowsWithColumns(Hbase.Text(tableName), toHbaseTextListFromByte(rows), toHbaseTextList(columns), toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	data = ret
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -341,12 +688,21 @@ func (client *HClient) GetRowsWithColumns(tableName string, rows [][]byte, colum
 //  - Timestamp: timestamp
 //  - Attributes: Get attributes
 func (client *HClient) GetRowsTs(tableName string, rows [][]byte, timestamp int64, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
-	ret, io, e1 := client.hbase.GetRowsTs(Hbase.Text(tableName), toHbaseTextListFromByte(rows), timestamp, toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetRowsTsContext(context.Background(), tableName, rows, timestamp, attributes)
+}
+
+// GetRowsTsContext is GetRowsTs with a context, see HClient.call.
+func (client *HClient) GetRowsTsContext(ctx context.Context, tableName string, rows [][]byte, timestamp int64, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
+	err = client.call(ctx, "getRowsTs", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetRowsTs(Hbase.Text(tableName), toHbaseTextListFromByte(rows), timestamp, toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	data = ret
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -360,12 +716,21 @@ func (client *HClient) GetRowsTs(tableName string, rows [][]byte, timestamp int6
 //  - Timestamp
 //  - Attributes: Get attributes
 func (client *HClient) GetRowsWithColumnsTs(tableName string, rows [][]byte, columns []string, timestamp int64, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
-	ret, io, e1 := client.hbase.GetRowsWithColumnsTs(Hbase.Text(tableName), toHbaseTextListFromByte(rows), toHbaseTextList(columns), timestamp, toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetRowsWithColumnsTsContext(context.Background(), tableName, rows, columns, timestamp, attributes)
+}
+
+// GetRowsWithColumnsTsContext is GetRowsWithColumnsTs with a context, see HClient.call.
+func (client *HClient) GetRowsWithColumnsTsContext(ctx context.Context, tableName string, rows [][]byte, columns []string, timestamp int64, attributes map[string]string) (data []*Hbase.TRowResult, err error) {
+	err = client.call(ctx, "getRowsWithColumnsTs", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetRowsWithColumnsTs(Hbase.Text(tableName), toHbaseTextListFromByte(rows), toHbaseTextList(columns), timestamp, toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	data = ret
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -379,7 +744,14 @@ func (client *HClient) GetRowsWithColumnsTs(tableName string, rows [][]byte, col
 //  - Mutations: list of mutation commands
 //  - Attributes: Mutation attributes
 func (client *HClient) MutateRow(tableName string, row []byte, mutations []*Hbase.Mutation, attributes map[string]string) error {
-	return checkHbaseArgError(client.hbase.MutateRow(Hbase.Text(tableName), Hbase.Text(row), mutations, toHbaseTextMap(attributes)))
+	return client.MutateRowContext(context.Background(), tableName, row, mutations, attributes)
+}
+
+// MutateRowContext is MutateRow with a context, see HClient.call.
+func (client *HClient) MutateRowContext(ctx context.Context, tableName string, row []byte, mutations []*Hbase.Mutation, attributes map[string]string) error {
+	return client.call(ctx, "mutateRow", tableName, func() error {
+		return checkHbaseArgError(client.hbase.MutateRow(Hbase.Text(tableName), Hbase.Text(row), mutations, toHbaseTextMap(attributes)))
+	})
 }
 
 // Apply a series of mutations (updates/deletes) to a row in a
@@ -393,7 +765,14 @@ func (client *HClient) MutateRow(tableName string, row []byte, mutations []*Hbas
 //  - Timestamp: timestamp
 //  - Attributes: Mutation attributes
 func (client *HClient) MutateRowTs(tableName string, row []byte, mutations []*Hbase.Mutation, timestamp int64, attributes map[string]string) error {
-	return checkHbaseArgError(client.hbase.MutateRowTs(Hbase.Text(tableName), Hbase.Text(row), mutations, timestamp, toHbaseTextMap(attributes)))
+	return client.MutateRowTsContext(context.Background(), tableName, row, mutations, timestamp, attributes)
+}
+
+// MutateRowTsContext is MutateRowTs with a context, see HClient.call.
+func (client *HClient) MutateRowTsContext(ctx context.Context, tableName string, row []byte, mutations []*Hbase.Mutation, timestamp int64, attributes map[string]string) error {
+	return client.call(ctx, "mutateRowTs", tableName, func() error {
+		return checkHbaseArgError(client.hbase.MutateRowTs(Hbase.Text(tableName), Hbase.Text(row), mutations, timestamp, toHbaseTextMap(attributes)))
+	})
 }
 
 // Apply a series of batches (each a series of mutations on a single row)
@@ -405,7 +784,14 @@ func (client *HClient) MutateRowTs(tableName string, row []byte, mutations []*Hb
 //  - RowBatches: list of row batches
 //  - Attributes: Mutation attributes
 func (client *HClient) MutateRows(tableName string, rowBatches []*Hbase.BatchMutation, attributes map[string]string) error {
-	return checkHbaseArgError(client.hbase.MutateRows(Hbase.Text(tableName), rowBatches, toHbaseTextMap(attributes)))
+	return client.MutateRowsContext(context.Background(), tableName, rowBatches, attributes)
+}
+
+// MutateRowsContext is MutateRows with a context, see HClient.call.
+func (client *HClient) MutateRowsContext(ctx context.Context, tableName string, rowBatches []*Hbase.BatchMutation, attributes map[string]string) error {
+	return client.call(ctx, "mutateRows", tableName, func() error {
+		return checkHbaseArgError(client.hbase.MutateRows(Hbase.Text(tableName), rowBatches, toHbaseTextMap(attributes)))
+	})
 }
 
 // Apply a series of batches (each a series of mutations on a single row)
@@ -418,7 +804,14 @@ func (client *HClient) MutateRows(tableName string, rowBatches []*Hbase.BatchMut
 //  - Timestamp: timestamp
 //  - Attributes: Mutation attributes
 func (client *HClient) MutateRowsTs(tableName string, rowBatches []*Hbase.BatchMutation, timestamp int64, attributes map[string]string) error {
-	return checkHbaseArgError(client.hbase.MutateRowsTs(Hbase.Text(tableName), rowBatches, timestamp, toHbaseTextMap(attributes)))
+	return client.MutateRowsTsContext(context.Background(), tableName, rowBatches, timestamp, attributes)
+}
+
+// MutateRowsTsContext is MutateRowsTs with a context, see HClient.call.
+func (client *HClient) MutateRowsTsContext(ctx context.Context, tableName string, rowBatches []*Hbase.BatchMutation, timestamp int64, attributes map[string]string) error {
+	return client.call(ctx, "mutateRowsTs", tableName, func() error {
+		return checkHbaseArgError(client.hbase.MutateRowsTs(Hbase.Text(tableName), rowBatches, timestamp, toHbaseTextMap(attributes)))
+	})
 }
 
 // Atomically increment the column value specified.  Returns the next value post increment.
@@ -428,12 +821,20 @@ func (client *HClient) MutateRowsTs(tableName string, rowBatches []*Hbase.BatchM
 //  - Column: name of column
 //  - Value: amount to increment by
 func (client *HClient) AtomicIncrement(tableName string, row []byte, column string, value int64) (v int64, err error) {
-	ret, io, ia, e1 := client.hbase.AtomicIncrement(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), value)
-	if err = checkHbaseArgError(io, ia, e1); err != nil {
-		return
-	}
+	return client.AtomicIncrementContext(context.Background(), tableName, row, column, value)
+}
+
+// AtomicIncrementContext is AtomicIncrement with a context, see HClient.call.
+func (client *HClient) AtomicIncrementContext(ctx context.Context, tableName string, row []byte, column string, value int64) (v int64, err error) {
+	err = client.call(ctx, "atomicIncrement", tableName, func() (err error) {
+		ret, io, ia, e1 := client.hbase.AtomicIncrement(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), value)
+		if err = checkHbaseArgError(io, ia, e1); err != nil {
+			return
+		}
 
-	v = ret
+		v = ret
+		return
+	})
 	return
 }
 
@@ -444,7 +845,14 @@ func (client *HClient) AtomicIncrement(tableName string, row []byte, column stri
 //  - Column: name of column whose value is to be deleted
 //  - Attributes: Delete attributes
 func (client *HClient) DeleteAll(tableName string, row []byte, column string, attributes map[string]string) error {
-	return checkError(client.hbase.DeleteAll(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), toHbaseTextMap(attributes)))
+	return client.DeleteAllContext(context.Background(), tableName, row, column, attributes)
+}
+
+// DeleteAllContext is DeleteAll with a context, see HClient.call.
+func (client *HClient) DeleteAllContext(ctx context.Context, tableName string, row []byte, column string, attributes map[string]string) error {
+	return client.call(ctx, "deleteAll", tableName, func() error {
+		return checkError(client.hbase.DeleteAll(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), toHbaseTextMap(attributes)))
+	})
 }
 
 // Delete all cells that match the passed row and column and whose
@@ -456,7 +864,14 @@ func (client *HClient) DeleteAll(tableName string, row []byte, column string, at
 //  - Timestamp: timestamp
 //  - Attributes: Delete attributes
 func (client *HClient) DeleteAllTs(tableName string, row []byte, column string, timestamp int64, attributes map[string]string) error {
-	return checkError(client.hbase.DeleteAllTs(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), timestamp, toHbaseTextMap(attributes)))
+	return client.DeleteAllTsContext(context.Background(), tableName, row, column, timestamp, attributes)
+}
+
+// DeleteAllTsContext is DeleteAllTs with a context, see HClient.call.
+func (client *HClient) DeleteAllTsContext(ctx context.Context, tableName string, row []byte, column string, timestamp int64, attributes map[string]string) error {
+	return client.call(ctx, "deleteAllTs", tableName, func() error {
+		return checkError(client.hbase.DeleteAllTs(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(column), timestamp, toHbaseTextMap(attributes)))
+	})
 }
 
 // Completely delete the row's cells.
@@ -465,7 +880,14 @@ func (client *HClient) DeleteAllTs(tableName string, row []byte, column string,
 //  - Row: key of the row to be completely deleted.
 //  - Attributes: Delete attributes
 func (client *HClient) DeleteAllRow(tableName string, row []byte, attributes map[string]string) error {
-	return checkError(client.hbase.DeleteAllRow(Hbase.Text(tableName), Hbase.Text(row), toHbaseTextMap(attributes)))
+	return client.DeleteAllRowContext(context.Background(), tableName, row, attributes)
+}
+
+// DeleteAllRowContext is DeleteAllRow with a context, see HClient.call.
+func (client *HClient) DeleteAllRowContext(ctx context.Context, tableName string, row []byte, attributes map[string]string) error {
+	return client.call(ctx, "deleteAllRow", tableName, func() error {
+		return checkError(client.hbase.DeleteAllRow(Hbase.Text(tableName), Hbase.Text(row), toHbaseTextMap(attributes)))
+	})
 }
 
 // Increment a cell by the ammount.
@@ -475,13 +897,27 @@ func (client *HClient) DeleteAllRow(tableName string, row []byte, attributes map
 // Parameters:
 //  - Increment: The single increment to apply
 func (client *HClient) Increment(increment *Hbase.TIncrement) error {
-	return checkError(client.hbase.Increment(increment))
+	return client.IncrementContext(context.Background(), increment)
+}
+
+// IncrementContext is Increment with a context, see HClient.call.
+func (client *HClient) IncrementContext(ctx context.Context, increment *Hbase.TIncrement) error {
+	return client.call(ctx, "increment", string(increment.Table), func() error {
+		return checkError(client.hbase.Increment(increment))
+	})
 }
 
 // Parameters:
 //  - Increments: The list of increments
 func (client *HClient) IncrementRows(increments []*Hbase.TIncrement) error {
-	return checkError(client.hbase.IncrementRows(increments))
+	return client.IncrementRowsContext(context.Background(), increments)
+}
+
+// IncrementRowsContext is IncrementRows with a context, see HClient.call.
+func (client *HClient) IncrementRowsContext(ctx context.Context, increments []*Hbase.TIncrement) error {
+	return client.call(ctx, "incrementRows", incrementsTable(increments), func() error {
+		return checkError(client.hbase.IncrementRows(increments))
+	})
 }
 
 // Completely delete the row's cells marked with a timestamp
@@ -492,7 +928,14 @@ func (client *HClient) IncrementRows(increments []*Hbase.TIncrement) error {
 //  - Timestamp: timestamp
 //  - Attributes: Delete attributes
 func (client *HClient) DeleteAllRowTs(tableName string, row []byte, timestamp int64, attributes map[string]string) error {
-	return checkError(client.hbase.DeleteAllRowTs(Hbase.Text(tableName), Hbase.Text(row), timestamp, toHbaseTextMap(attributes)))
+	return client.DeleteAllRowTsContext(context.Background(), tableName, row, timestamp, attributes)
+}
+
+// DeleteAllRowTsContext is DeleteAllRowTs with a context, see HClient.call.
+func (client *HClient) DeleteAllRowTsContext(ctx context.Context, tableName string, row []byte, timestamp int64, attributes map[string]string) error {
+	return client.call(ctx, "deleteAllRowTs", tableName, func() error {
+		return checkError(client.hbase.DeleteAllRowTs(Hbase.Text(tableName), Hbase.Text(row), timestamp, toHbaseTextMap(attributes)))
+	})
 }
 
 // Get a scanner on the current table, using the Scan instance
@@ -502,12 +945,27 @@ func (client *HClient) DeleteAllRowTs(tableName string, row []byte, timestamp in
 //  - Scan: Scan instance
 //  - Attributes: Scan attributes
 func (client *HClient) ScannerOpenWithScan(tableName string, scan *TScan, attributes map[string]string) (id int32, err error) {
-	ret, io, e1 := client.hbase.ScannerOpenWithScan(Hbase.Text(tableName), toHbaseTScan(scan), toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
+	return client.ScannerOpenWithScanContext(context.Background(), tableName, scan, attributes)
+}
+
+// ScannerOpenWithScanContext is ScannerOpenWithScan with a context, see HClient.call.
+func (client *HClient) ScannerOpenWithScanContext(ctx context.Context, tableName string, scan *TScan, attributes map[string]string) (id int32, err error) {
+	if client.validate && scan != nil && scan.FilterString != "" {
+		// returned as is, a bad filter says nothing about the connection
+		if err = filter.Validate(scan.FilterString); err != nil {
+			return
+		}
 	}
+	err = client.call(ctx, "scannerOpenWithScan", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.ScannerOpenWithScan(Hbase.Text(tableName), toHbaseTScan(scan), toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	id = int32(ret)
+		id = int32(ret)
+		client.openedScanner(id, tableName)
+		return
+	})
 	return
 }
 
@@ -523,12 +981,21 @@ func (client *HClient) ScannerOpenWithScan(tableName string, scan *TScan, attrib
 // to pass a regex in the column qualifier.
 //  - Attributes: Scan attributes
 func (client *HClient) ScannerOpen(tableName string, startRow []byte, columns []string, attributes map[string]string) (id int32, err error) {
-	ret, io, e1 := client.hbase.ScannerOpen(Hbase.Text(tableName), Hbase.Text(startRow), toHbaseTextList(columns), toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.ScannerOpenContext(context.Background(), tableName, startRow, columns, attributes)
+}
+
+// ScannerOpenContext is ScannerOpen with a context, see HClient.call.
+func (client *HClient) ScannerOpenContext(ctx context.Context, tableName string, startRow []byte, columns []string, attributes map[string]string) (id int32, err error) {
+	err = client.call(ctx, "scannerOpen", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.ScannerOpen(Hbase.Text(tableName), Hbase.Text(startRow), toHbaseTextList(columns), toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	id = int32(ret)
+		id = int32(ret)
+		client.openedScanner(id, tableName)
+		return
+	})
 	return
 }
 
@@ -547,12 +1014,21 @@ func (client *HClient) ScannerOpen(tableName string, startRow []byte, columns []
 // to pass a regex in the column qualifier.
 //  - Attributes: Scan attributes
 func (client *HClient) ScannerOpenWithStop(tableName string, startRow []byte, stopRow []byte, columns []string, attributes map[string]string) (id int32, err error) {
-	ret, io, e1 := client.hbase.ScannerOpenWithStop(Hbase.Text(tableName), Hbase.Text(startRow), Hbase.Text(stopRow), toHbaseTextList(columns), toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.ScannerOpenWithStopContext(context.Background(), tableName, startRow, stopRow, columns, attributes)
+}
 
-	id = int32(ret)
+// ScannerOpenWithStopContext is ScannerOpenWithStop with a context, see HClient.call.
+func (client *HClient) ScannerOpenWithStopContext(ctx context.Context, tableName string, startRow []byte, stopRow []byte, columns []string, attributes map[string]string) (id int32, err error) {
+	err = client.call(ctx, "scannerOpenWithStop", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.ScannerOpenWithStop(Hbase.Text(tableName), Hbase.Text(startRow), Hbase.Text(stopRow), toHbaseTextList(columns), toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
+
+		id = int32(ret)
+		client.openedScanner(id, tableName)
+		return
+	})
 	return
 }
 
@@ -565,12 +1041,21 @@ func (client *HClient) ScannerOpenWithStop(tableName string, startRow []byte, st
 //  - Columns: the columns you want returned
 //  - Attributes: Scan attributes
 func (client *HClient) ScannerOpenWithPrefix(tableName string, startAndPrefix []byte, columns []string, attributes map[string]string) (id int32, err error) {
-	ret, io, e1 := client.hbase.ScannerOpenWithPrefix(Hbase.Text(tableName), Hbase.Text(startAndPrefix), toHbaseTextList(columns), toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.ScannerOpenWithPrefixContext(context.Background(), tableName, startAndPrefix, columns, attributes)
+}
+
+// ScannerOpenWithPrefixContext is ScannerOpenWithPrefix with a context, see HClient.call.
+func (client *HClient) ScannerOpenWithPrefixContext(ctx context.Context, tableName string, startAndPrefix []byte, columns []string, attributes map[string]string) (id int32, err error) {
+	err = client.call(ctx, "scannerOpenWithPrefix", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.ScannerOpenWithPrefix(Hbase.Text(tableName), Hbase.Text(startAndPrefix), toHbaseTextList(columns), toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	id = int32(ret)
+		id = int32(ret)
+		client.openedScanner(id, tableName)
+		return
+	})
 	return
 }
 
@@ -588,12 +1073,21 @@ func (client *HClient) ScannerOpenWithPrefix(tableName string, startAndPrefix []
 //  - Timestamp: timestamp
 //  - Attributes: Scan attributes
 func (client *HClient) ScannerOpenTs(tableName string, startRow []byte, columns []string, timestamp int64, attributes map[string]string) (id int32, err error) {
-	ret, io, e1 := client.hbase.ScannerOpenTs(Hbase.Text(tableName), Hbase.Text(startRow), toHbaseTextList(columns), timestamp, toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.ScannerOpenTsContext(context.Background(), tableName, startRow, columns, timestamp, attributes)
+}
 
-	id = int32(ret)
+// ScannerOpenTsContext is ScannerOpenTs with a context, see HClient.call.
+func (client *HClient) ScannerOpenTsContext(ctx context.Context, tableName string, startRow []byte, columns []string, timestamp int64, attributes map[string]string) (id int32, err error) {
+	err = client.call(ctx, "scannerOpenTs", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.ScannerOpenTs(Hbase.Text(tableName), Hbase.Text(startRow), toHbaseTextList(columns), timestamp, toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
+
+		id = int32(ret)
+		client.openedScanner(id, tableName)
+		return
+	})
 	return
 }
 
@@ -614,12 +1108,21 @@ func (client *HClient) ScannerOpenTs(tableName string, startRow []byte, columns
 //  - Timestamp: timestamp
 //  - Attributes: Scan attributes
 func (client *HClient) ScannerOpenWithStopTs(tableName string, startRow []byte, stopRow []byte, columns []string, timestamp int64, attributes map[string]string) (id int32, err error) {
-	ret, io, e1 := client.hbase.ScannerOpenWithStopTs(Hbase.Text(tableName), Hbase.Text(startRow), Hbase.Text(stopRow), toHbaseTextList(columns), timestamp, toHbaseTextMap(attributes))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.ScannerOpenWithStopTsContext(context.Background(), tableName, startRow, stopRow, columns, timestamp, attributes)
+}
+
+// ScannerOpenWithStopTsContext is ScannerOpenWithStopTs with a context, see HClient.call.
+func (client *HClient) ScannerOpenWithStopTsContext(ctx context.Context, tableName string, startRow []byte, stopRow []byte, columns []string, timestamp int64, attributes map[string]string) (id int32, err error) {
+	err = client.call(ctx, "scannerOpenWithStopTs", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.ScannerOpenWithStopTs(Hbase.Text(tableName), Hbase.Text(startRow), Hbase.Text(stopRow), toHbaseTextList(columns), timestamp, toHbaseTextMap(attributes))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	id = int32(ret)
+		id = int32(ret)
+		client.openedScanner(id, tableName)
+		return
+	})
 	return
 }
 
@@ -633,12 +1136,21 @@ func (client *HClient) ScannerOpenWithStopTs(tableName string, startRow []byte,
 // Parameters:
 //  - Id: id of a scanner returned by scannerOpen
 func (client *HClient) ScannerGet(id int32) (data []*Hbase.TRowResult, err error) {
-	ret, io, ia, e1 := client.hbase.ScannerGet(Hbase.ScannerID(id))
-	if err = checkHbaseArgError(io, ia, e1); err != nil {
-		return
-	}
+	return client.ScannerGetContext(context.Background(), id)
+}
 
-	data = ret
+// ScannerGetContext is ScannerGet with a context, see HClient.call.
+func (client *HClient) ScannerGetContext(ctx context.Context, id int32) (data []*Hbase.TRowResult, err error) {
+	err = client.call(ctx, "scannerGet", client.scannerTable(id), func() (err error) {
+		ret, io, ia, e1 := client.hbase.ScannerGet(Hbase.ScannerID(id))
+		if err = checkHbaseArgError(io, ia, e1); err != nil {
+			return
+		}
+
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -653,12 +1165,21 @@ func (client *HClient) ScannerGet(id int32) (data []*Hbase.TRowResult, err error
 //  - Id: id of a scanner returned by scannerOpen
 //  - NbRows: number of results to return
 func (client *HClient) ScannerGetList(id int32, nbRows int32) (data []*Hbase.TRowResult, err error) {
-	ret, io, ia, e1 := client.hbase.ScannerGetList(Hbase.ScannerID(id), nbRows)
-	if err = checkHbaseArgError(io, ia, e1); err != nil {
-		return
-	}
+	return client.ScannerGetListContext(context.Background(), id, nbRows)
+}
+
+// ScannerGetListContext is ScannerGetList with a context, see HClient.call.
+func (client *HClient) ScannerGetListContext(ctx context.Context, id int32, nbRows int32) (data []*Hbase.TRowResult, err error) {
+	err = client.call(ctx, "scannerGetList", client.scannerTable(id), func() (err error) {
+		ret, io, ia, e1 := client.hbase.ScannerGetList(Hbase.ScannerID(id), nbRows)
+		if err = checkHbaseArgError(io, ia, e1); err != nil {
+			return
+		}
 
-	data = ret
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -667,7 +1188,16 @@ func (client *HClient) ScannerGetList(id int32, nbRows int32) (data []*Hbase.TRo
 // Parameters:
 //  - Id: id of a scanner returned by scannerOpen
 func (client *HClient) ScannerClose(id int32) error {
-	return checkHbaseArgError(client.hbase.ScannerClose(Hbase.ScannerID(id)))
+	return client.ScannerCloseContext(context.Background(), id)
+}
+
+// ScannerCloseContext is ScannerClose with a context, see HClient.call.
+func (client *HClient) ScannerCloseContext(ctx context.Context, id int32) error {
+	err := client.call(ctx, "scannerClose", client.scannerTable(id), func() error {
+		return checkHbaseArgError(client.hbase.ScannerClose(Hbase.ScannerID(id)))
+	})
+	client.closedScanner(id)
+	return err
 }
 
 // Get the row just before the specified one.
@@ -677,12 +1207,21 @@ func (client *HClient) ScannerClose(id int32) error {
 //  - Row: row key
 //  - Family: column name
 func (client *HClient) GetRowOrBefore(tableName string, row string, family string) (data []*Hbase.TCell, err error) {
-	ret, io, e1 := client.hbase.GetRowOrBefore(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(family))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetRowOrBeforeContext(context.Background(), tableName, row, family)
+}
+
+// GetRowOrBeforeContext is GetRowOrBefore with a context, see HClient.call.
+func (client *HClient) GetRowOrBeforeContext(ctx context.Context, tableName string, row string, family string) (data []*Hbase.TCell, err error) {
+	err = client.call(ctx, "getRowOrBefore", tableName, func() (err error) {
+		ret, io, e1 := client.hbase.GetRowOrBefore(Hbase.Text(tableName), Hbase.Text(row), Hbase.Text(family))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
 
-	data = ret
+		client.returned(len(ret))
+		data = ret
+		return
+	})
 	return
 }
 
@@ -692,11 +1231,19 @@ func (client *HClient) GetRowOrBefore(tableName string, row string, family strin
 // Parameters:
 //  - Row: row key
 func (client *HClient) GetRegionInfo(row string) (region *TRegionInfo, err error) {
-	ret, io, e1 := client.hbase.GetRegionInfo(Hbase.Text(row))
-	if err = checkError(io, e1); err != nil {
-		return
-	}
+	return client.GetRegionInfoContext(context.Background(), row)
+}
 
-	region = toRegion(ret)
+// GetRegionInfoContext is GetRegionInfo with a context, see HClient.call.
+func (client *HClient) GetRegionInfoContext(ctx context.Context, row string) (region *TRegionInfo, err error) {
+	err = client.call(ctx, "getRegionInfo", "", func() (err error) {
+		ret, io, e1 := client.hbase.GetRegionInfo(Hbase.Text(row))
+		if err = checkError(io, e1); err != nil {
+			return
+		}
+
+		region = toRegion(ret)
+		return
+	})
 	return
 }
@@ -0,0 +1,443 @@
+package hbase
+
+import (
+	"context"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/J-J-J/hbase/Hbase"
+)
+
+// ValueMarshaler is implemented by field types encoding their own cell
+// value. encoding.BinaryMarshaler is used when it is not.
+type ValueMarshaler interface {
+	MarshalHbase() ([]byte, error)
+}
+
+// ValueUnmarshaler is implemented by field types decoding their own cell
+// value. encoding.BinaryUnmarshaler is used when it is not.
+type ValueUnmarshaler interface {
+	UnmarshalHbase(value []byte) error
+}
+
+var (
+	valueMarshalerType    = reflect.TypeOf((*ValueMarshaler)(nil)).Elem()
+	valueUnmarshalerType  = reflect.TypeOf((*ValueUnmarshaler)(nil)).Elem()
+	binaryMarshalerType   = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+	timeType              = reflect.TypeOf(time.Time{})
+)
+
+// rowKeyTag tags the field holding the row key.
+const rowKeyTag = "rowkey"
+
+// structField is a tagged field of a struct.
+type structField struct {
+	index     []int
+	column    string // empty for the row key
+	omitEmpty bool
+}
+
+// structInfo is the mapping of a struct type.
+type structInfo struct {
+	rowKey  *structField
+	columns []*structField
+}
+
+var structInfos sync.Map // reflect.Type -> *structInfo
+
+// getStructInfo return the mapping of t, a struct type.
+func getStructInfo(t reflect.Type) (*structInfo, error) {
+	if info, ok := structInfos.Load(t); ok {
+		return info.(*structInfo), nil
+	}
+	info := &structInfo{}
+	if err := info.add(t, nil); err != nil {
+		return nil, err
+	}
+	structInfos.Store(t, info)
+	return info, nil
+}
+
+// add maps the fields of t, embedded untagged structs being flattened.
+func (info *structInfo) add(t reflect.Type, index []int) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup("hbase")
+		fieldIndex := append(append([]int(nil), index...), i)
+		if !tagged {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if f.Anonymous && ft.Kind() == reflect.Struct {
+				if err := info.add(ft, fieldIndex); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if tag == "-" {
+			continue
+		}
+		if f.PkgPath != "" {
+			return fmt.Errorf("hbase: tagged field %s.%s is unexported", t, f.Name)
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		field := &structField{index: fieldIndex, omitEmpty: opts == "omitempty"}
+		if opts != "" && opts != "omitempty" {
+			return fmt.Errorf("hbase: field %s.%s has unknown tag option %q", t, f.Name, opts)
+		}
+		switch {
+		case name == rowKeyTag:
+			if info.rowKey != nil {
+				return fmt.Errorf("hbase: %s has two row key fields", t)
+			}
+			info.rowKey = field
+		case strings.Contains(name, ":"):
+			field.column = name
+			info.columns = append(info.columns, field)
+		default:
+			return fmt.Errorf("hbase: field %s.%s tag %q is neither %q nor family:qualifier", t, f.Name, name, rowKeyTag)
+		}
+	}
+	return nil
+}
+
+// columnNames return the tagged columns.
+func (info *structInfo) columnNames() []string {
+	names := make([]string, len(info.columns))
+	for i, field := range info.columns {
+		names[i] = field.column
+	}
+	return names
+}
+
+// structValue return the struct v points to.
+func structValue(v interface{}) (reflect.Value, *structInfo, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, nil, fmt.Errorf("hbase: %T is not a struct or a pointer to a struct", v)
+	}
+	if !rv.CanAddr() {
+		// a copy, so that methods on pointers are found
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		rv = p.Elem()
+	}
+	info, err := getStructInfo(rv.Type())
+	return rv, info, err
+}
+
+// fieldByIndex return the field at index, nil embedded pointers being
+// allocated when alloc is true, or an invalid Value.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// Marshal encodes the struct v, or a pointer to it, into its row key and a
+// put for every tagged column. Fields are tagged with hbase:"rowkey" for the
+// row key, hbase:"family:qualifier" for a column, optionally followed by
+// ",omitempty" to skip zero values, and hbase:"-" to be ignored. Nil
+// pointers are skipped, so pointers hold optional columns.
+//
+// Strings and []byte are stored as is, integers and floats big-endian in
+// their size like the Java Bytes class, int and uint on 8 bytes, bools on one
+// byte and time.Time as int64 milliseconds since the epoch. Other types must
+// implement ValueMarshaler or encoding.BinaryMarshaler.
+func Marshal(v interface{}) ([]byte, []*Hbase.Mutation, error) {
+	rv, info, err := structValue(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	var row []byte
+	if info.rowKey != nil {
+		if f := fieldByIndex(rv, info.rowKey.index, false); f.IsValid() {
+			if row, err = encodeValue(f); err != nil {
+				return nil, nil, fmt.Errorf("hbase: row key: %v", err)
+			}
+		}
+	}
+	mutations := make([]*Hbase.Mutation, 0, len(info.columns))
+	for _, field := range info.columns {
+		f := fieldByIndex(rv, field.index, false)
+		if !f.IsValid() || f.Kind() == reflect.Ptr && f.IsNil() || field.omitEmpty && f.IsZero() {
+			continue
+		}
+		value, err := encodeValue(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("hbase: column %s: %v", field.column, err)
+		}
+		mutations = append(mutations, NewMutation(field.column, value))
+	}
+	return row, mutations, nil
+}
+
+// Unmarshal decodes row into the struct v points to, see Marshal. Columns
+// missing from row leave their field unchanged, and pointer fields are
+// allocated when their column is present.
+func Unmarshal(row *Hbase.TRowResult, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("hbase: Unmarshal needs a non-nil pointer, got %T", v)
+	}
+	if row == nil {
+		return errors.New("hbase: Unmarshal of a nil row")
+	}
+	rv, info, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	if info.rowKey != nil {
+		if err := decodeValue(fieldByIndex(rv, info.rowKey.index, true), row.Row); err != nil {
+			return fmt.Errorf("hbase: row key: %v", err)
+		}
+	}
+	for _, field := range info.columns {
+		cell, ok := row.Columns[field.column]
+		if !ok || cell == nil {
+			continue
+		}
+		if err := decodeValue(fieldByIndex(rv, field.index, true), cell.Value); err != nil {
+			return fmt.Errorf("hbase: column %s: %v", field.column, err)
+		}
+	}
+	return nil
+}
+
+// encodeValue encodes a field value.
+func encodeValue(v reflect.Value) ([]byte, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem() != timeType && marshals(v.Type()) {
+			return marshalValue(v)
+		}
+		v = v.Elem()
+	}
+	// time.Time is a BinaryMarshaler, but is stored the way Java does
+	if v.Type() == timeType {
+		return encodeUint(uint64(v.Interface().(time.Time).UnixMilli()), 8), nil
+	}
+	if marshals(v.Type()) {
+		return marshalValue(v)
+	}
+	if v.CanAddr() && marshals(reflect.PointerTo(v.Type())) {
+		return marshalValue(v.Addr())
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return []byte(v.String()), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), nil
+		}
+	case reflect.Bool:
+		if v.Bool() {
+			return []byte{0xff}, nil
+		}
+		return []byte{0}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return encodeUint(uint64(v.Int()), intSize(v.Type())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return encodeUint(v.Uint(), intSize(v.Type())), nil
+	case reflect.Float32:
+		return encodeUint(uint64(math.Float32bits(float32(v.Float()))), 4), nil
+	case reflect.Float64:
+		return encodeUint(math.Float64bits(v.Float()), 8), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func marshals(t reflect.Type) bool {
+	return t.Implements(valueMarshalerType) || t.Implements(binaryMarshalerType)
+}
+
+func unmarshals(t reflect.Type) bool {
+	return t.Implements(valueUnmarshalerType) || t.Implements(binaryUnmarshalerType)
+}
+
+func marshalValue(v reflect.Value) ([]byte, error) {
+	if m, ok := v.Interface().(ValueMarshaler); ok {
+		return m.MarshalHbase()
+	}
+	return v.Interface().(encoding.BinaryMarshaler).MarshalBinary()
+}
+
+// intSize return the size in bytes of the integer type t, 8 for int and
+// uint whatever the platform so that their values read the same everywhere.
+func intSize(t reflect.Type) int {
+	if k := t.Kind(); k == reflect.Int || k == reflect.Uint {
+		return 8
+	}
+	return int(t.Size())
+}
+
+// encodeUint return the size low bytes of n, big-endian.
+func encodeUint(n uint64, size int) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], n)
+	return append([]byte(nil), b[8-size:]...)
+}
+
+// decodeValue decodes value into v, a settable field.
+func decodeValue(v reflect.Value, value []byte) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		n, err := decodeUint(value, 8)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(time.UnixMilli(int64(n))))
+		return nil
+	}
+	if pv := v.Addr(); unmarshals(pv.Type()) {
+		return unmarshalValue(pv, value)
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(string(value))
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(append([]byte(nil), value...))
+			return nil
+		}
+	case reflect.Bool:
+		if len(value) != 1 {
+			return fmt.Errorf("bool needs 1 byte, got %d", len(value))
+		}
+		v.SetBool(value[0] != 0)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size := intSize(v.Type())
+		n, err := decodeUint(value, size)
+		if err != nil {
+			return err
+		}
+		// sign extend
+		shift := uint(64 - 8*size)
+		i := int64(n<<shift) >> shift
+		if v.OverflowInt(i) {
+			return fmt.Errorf("%d overflows %s", i, v.Type())
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := decodeUint(value, intSize(v.Type()))
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(n) {
+			return fmt.Errorf("%d overflows %s", n, v.Type())
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Float32:
+		n, err := decodeUint(value, 4)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(float64(math.Float32frombits(uint32(n))))
+		return nil
+	case reflect.Float64:
+		n, err := decodeUint(value, 8)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(math.Float64frombits(n))
+		return nil
+	}
+	return fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func unmarshalValue(v reflect.Value, value []byte) error {
+	if u, ok := v.Interface().(ValueUnmarshaler); ok {
+		return u.UnmarshalHbase(value)
+	}
+	return v.Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(value)
+}
+
+// decodeUint decodes a big-endian unsigned integer of size bytes.
+func decodeUint(value []byte, size int) (uint64, error) {
+	if len(value) != size {
+		return 0, fmt.Errorf("need %d bytes, got %d", size, len(value))
+	}
+	var n uint64
+	for _, b := range value {
+		n = n<<8 | uint64(b)
+	}
+	return n, nil
+}
+
+// PutStruct writes the tagged columns of v, a struct or a pointer to one,
+// to the row of its row key, see Marshal.
+func (client *HClient) PutStruct(tableName string, v interface{}, attributes map[string]string) error {
+	return client.PutStructContext(context.Background(), tableName, v, attributes)
+}
+
+// PutStructContext is PutStruct with a context.
+func (client *HClient) PutStructContext(ctx context.Context, tableName string, v interface{}, attributes map[string]string) error {
+	row, mutations, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(row) == 0 {
+		return errors.New("hbase: PutStruct needs a non-empty row key")
+	}
+	if len(mutations) == 0 {
+		return nil
+	}
+	return client.MutateRowContext(ctx, tableName, row, mutations, attributes)
+}
+
+// GetStruct reads the tagged columns of row into the struct v points to, see
+// Unmarshal. found is false when the row has none of them, v being then left
+// unchanged.
+func (client *HClient) GetStruct(tableName string, row []byte, v interface{}, attributes map[string]string) (found bool, err error) {
+	return client.GetStructContext(context.Background(), tableName, row, v, attributes)
+}
+
+// GetStructContext is GetStruct with a context.
+func (client *HClient) GetStructContext(ctx context.Context, tableName string, row []byte, v interface{}, attributes map[string]string) (found bool, err error) {
+	_, info, err := structValue(v)
+	if err != nil {
+		return false, err
+	}
+	results, err := client.GetRowWithColumnsContext(ctx, tableName, row, info.columnNames(), attributes)
+	if err != nil || len(results) == 0 {
+		return false, err
+	}
+	return true, Unmarshal(results[0], v)
+}
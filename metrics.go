@@ -0,0 +1,298 @@
+package hbase
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency
+// histograms of Metrics.
+var DefaultLatencyBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// DefaultSizeBuckets are the upper bounds, in bytes, of the payload
+// histograms of Metrics.
+var DefaultSizeBuckets = []float64{64, 256, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20, 16 << 20}
+
+// Histogram counts values in buckets, as a Prometheus histogram.
+type Histogram struct {
+	// Bounds are the increasing upper bounds of the buckets, a last bucket
+	// holding the values above them.
+	Bounds []float64
+	// Counts are the number of values of every bucket, Counts[i] those in
+	// (Bounds[i-1], Bounds[i]]. Counts has one more element than Bounds.
+	Counts []uint64
+	Count  uint64  // number of values
+	Sum    float64 // sum of the values
+}
+
+// NewHistogram return an empty Histogram with the given bounds.
+func NewHistogram(bounds []float64) Histogram {
+	return Histogram{Bounds: bounds, Counts: make([]uint64, len(bounds)+1)}
+}
+
+// Observe adds v to the histogram.
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.Bounds, v)
+	h.Counts[i]++
+	h.Count++
+	h.Sum += v
+}
+
+// Mean return the average of the values, 0 when empty.
+func (h *Histogram) Mean() float64 {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / float64(h.Count)
+}
+
+// Quantile estimates the q-quantile of the values, q in [0, 1], by linear
+// interpolation within the bucket holding it like the histogram_quantile of
+// Prometheus. Values above the last bound estimate to that bound. It return
+// NaN when the histogram is empty.
+func (h *Histogram) Quantile(q float64) float64 {
+	if h.Count == 0 {
+		return math.NaN()
+	}
+	rank := q * float64(h.Count)
+	var seen uint64
+	for i, n := range h.Counts {
+		if n == 0 || float64(seen+n) < rank {
+			seen += n
+			continue
+		}
+		if i == len(h.Bounds) {
+			break
+		}
+		lower := 0.0
+		if i > 0 {
+			lower = h.Bounds[i-1]
+		}
+		return lower + (h.Bounds[i]-lower)*(rank-float64(seen))/float64(n)
+	}
+	if len(h.Bounds) == 0 {
+		return math.NaN()
+	}
+	return h.Bounds[len(h.Bounds)-1]
+}
+
+func (h *Histogram) clone() Histogram {
+	c := *h
+	c.Counts = append([]uint64(nil), h.Counts...)
+	return c
+}
+
+// CallKey identifies the calls aggregated by Metrics.
+type CallKey struct {
+	Method     string
+	Table      string
+	ErrorClass string
+}
+
+// CallStats aggregate the calls of a CallKey.
+type CallStats struct {
+	Latency       Histogram // seconds, its Count being the number of calls
+	BytesSent     Histogram
+	BytesReceived Histogram
+	Rows          uint64 // rows or cells returned
+}
+
+// Metrics is an Observer keeping in memory the histograms of the calls per
+// method, table and error class. The zero value is ready to use:
+//
+//	metrics := &hbase.Metrics{}
+//	client, err := hbase.NewTCPClient(addr, true, hbase.WithObserver(metrics))
+//	...
+//	for key, stats := range metrics.Snapshot() {
+//		fmt.Println(key.Method, key.Table, stats.Latency.Count, stats.Latency.Quantile(0.99))
+//	}
+//
+// Metrics is also an http.Handler serving the histograms in the Prometheus
+// text format, to be scraped directly:
+//
+//	http.Handle("/metrics", metrics)
+type Metrics struct {
+	// LatencyBuckets are the bounds of the latency histograms in seconds,
+	// DefaultLatencyBuckets when nil. SizeBuckets are the bounds of the
+	// payload histograms in bytes, DefaultSizeBuckets when nil. They are
+	// set before the first call observed.
+	LatencyBuckets []float64
+	SizeBuckets    []float64
+
+	mu    sync.Mutex
+	stats map[CallKey]*CallStats
+}
+
+// ObserveCall adds info to the histograms.
+func (m *Metrics) ObserveCall(info CallInfo) {
+	key := CallKey{Method: info.Method, Table: info.Table, ErrorClass: info.ErrorClass}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.stats[key]
+	if s == nil {
+		latency, size := m.LatencyBuckets, m.SizeBuckets
+		if latency == nil {
+			latency = DefaultLatencyBuckets
+		}
+		if size == nil {
+			size = DefaultSizeBuckets
+		}
+		s = &CallStats{
+			Latency:       NewHistogram(latency),
+			BytesSent:     NewHistogram(size),
+			BytesReceived: NewHistogram(size),
+		}
+		if m.stats == nil {
+			m.stats = make(map[CallKey]*CallStats)
+		}
+		m.stats[key] = s
+	}
+	s.Latency.Observe(info.Duration.Seconds())
+	s.BytesSent.Observe(float64(info.BytesSent))
+	s.BytesReceived.Observe(float64(info.BytesReceived))
+	s.Rows += uint64(info.Rows)
+}
+
+// Snapshot return a copy of the statistics of the calls observed so far.
+func (m *Metrics) Snapshot() map[CallKey]CallStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snap := make(map[CallKey]CallStats, len(m.stats))
+	for key, s := range m.stats {
+		snap[key] = CallStats{
+			Latency:       s.Latency.clone(),
+			BytesSent:     s.BytesSent.clone(),
+			BytesReceived: s.BytesReceived.clone(),
+			Rows:          s.Rows,
+		}
+	}
+	return snap
+}
+
+// Reset forgets the calls observed so far.
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	m.stats = nil
+	m.mu.Unlock()
+}
+
+// WritePrometheus writes the statistics in the Prometheus text exposition
+// format, as the histograms hbase_client_call_duration_seconds,
+// hbase_client_call_sent_bytes and hbase_client_call_received_bytes and the
+// counter hbase_client_call_rows_total, labelled with PrometheusLabels.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	snap := m.Snapshot()
+	keys := make([]CallKey, 0, len(snap))
+	for key := range snap {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.Table != b.Table {
+			return a.Table < b.Table
+		}
+		return a.ErrorClass < b.ErrorClass
+	})
+
+	bw := bufio.NewWriter(w)
+	histogram := func(name, help string, h func(*CallStats) *Histogram) {
+		bw.WriteString("# HELP " + name + " " + help + "\n# TYPE " + name + " histogram\n")
+		for _, key := range keys {
+			s := snap[key]
+			hist := h(&s)
+			labels := promLabels(key)
+			var cumulative uint64
+			for i, n := range hist.Counts {
+				cumulative += n
+				le := "+Inf"
+				if i < len(hist.Bounds) {
+					le = strconv.FormatFloat(hist.Bounds[i], 'g', -1, 64)
+				}
+				bw.WriteString(name + "_bucket{" + labels + `,le="` + le + `"} ` + strconv.FormatUint(cumulative, 10) + "\n")
+			}
+			bw.WriteString(name + "_sum{" + labels + "} " + strconv.FormatFloat(hist.Sum, 'g', -1, 64) + "\n")
+			bw.WriteString(name + "_count{" + labels + "} " + strconv.FormatUint(hist.Count, 10) + "\n")
+		}
+	}
+	histogram("hbase_client_call_duration_seconds", "Duration of the calls to the HBase thrift gateway.",
+		func(s *CallStats) *Histogram { return &s.Latency })
+	histogram("hbase_client_call_sent_bytes", "Size of the requests to the HBase thrift gateway.",
+		func(s *CallStats) *Histogram { return &s.BytesSent })
+	histogram("hbase_client_call_received_bytes", "Size of the replies of the HBase thrift gateway.",
+		func(s *CallStats) *Histogram { return &s.BytesReceived })
+
+	const rows = "hbase_client_call_rows_total"
+	bw.WriteString("# HELP " + rows + " Rows, or cells, returned by the HBase thrift gateway.\n# TYPE " + rows + " counter\n")
+	for _, key := range keys {
+		bw.WriteString(rows + "{" + promLabels(key) + "} " + strconv.FormatUint(snap[key].Rows, 10) + "\n")
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP serves WritePrometheus.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WritePrometheus(w)
+}
+
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func promLabels(key CallKey) string {
+	return `method="` + promEscaper.Replace(key.Method) +
+		`",table="` + promEscaper.Replace(key.Table) +
+		`",error_class="` + promEscaper.Replace(key.ErrorClass) + `"`
+}
+
+// PrometheusLabels are the label names of the metrics of Metrics and
+// PrometheusObserver, the values being the Method, Table and ErrorClass of
+// a CallInfo.
+var PrometheusLabels = []string{"method", "table", "error_class"}
+
+// PrometheusObserver is an Observer feeding vectors of the Prometheus
+// client, which this package does not depend on. Every field is optional
+// and receives the values of PrometheusLabels with a value of the call:
+//
+//	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+//		Name: "hbase_client_call_duration_seconds",
+//	}, hbase.PrometheusLabels)
+//	rows := prometheus.NewCounterVec(prometheus.CounterOpts{
+//		Name: "hbase_client_call_rows_total",
+//	}, hbase.PrometheusLabels)
+//	prometheus.MustRegister(duration, rows)
+//	observer := &hbase.PrometheusObserver{
+//		Duration: func(labels []string, v float64) { duration.WithLabelValues(labels...).Observe(v) },
+//		Rows:     func(labels []string, v float64) { rows.WithLabelValues(labels...).Add(v) },
+//	}
+//	client, err := hbase.NewTCPClient(addr, true, hbase.WithObserver(observer))
+type PrometheusObserver struct {
+	Duration      func(labels []string, seconds float64)
+	BytesSent     func(labels []string, bytes float64)
+	BytesReceived func(labels []string, bytes float64)
+	Rows          func(labels []string, rows float64)
+}
+
+// ObserveCall passes info to the fields set.
+func (p *PrometheusObserver) ObserveCall(info CallInfo) {
+	labels := []string{info.Method, info.Table, info.ErrorClass}
+	if p.Duration != nil {
+		p.Duration(labels, info.Duration.Seconds())
+	}
+	if p.BytesSent != nil {
+		p.BytesSent(labels, float64(info.BytesSent))
+	}
+	if p.BytesReceived != nil {
+		p.BytesReceived(labels, float64(info.BytesReceived))
+	}
+	if p.Rows != nil {
+		p.Rows(labels, float64(info.Rows))
+	}
+}
@@ -0,0 +1,287 @@
+package hbase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/J-J-J/hbase/Hbase"
+)
+
+// ErrMutatorClosed is returned by the calls made on a closed BufferedMutator.
+var ErrMutatorClosed = errors.New("hbase: buffered mutator is closed")
+
+// BufferedMutatorConfig configures a BufferedMutator, zero values use the
+// defaults.
+type BufferedMutatorConfig struct {
+	// FlushBytes flushes the buffer once its mutations reach this size,
+	// default 2 MiB.
+	FlushBytes int
+	// FlushRows flushes the buffer once it holds this many rows, default
+	// 1000.
+	FlushRows int
+	// FlushInterval bounds how long a mutation stays buffered, default 1s,
+	// a negative value flushes on size and row count only.
+	FlushInterval time.Duration
+	// MaxPendingBytes bounds the size of the mutations buffered or being
+	// written, Mutate blocks beyond it. Default 4 * FlushBytes, and at
+	// least FlushBytes.
+	MaxPendingBytes int
+	// Attributes are sent with every MutateRows call.
+	Attributes map[string]string
+	// OnError is called with the batch of every failed MutateRows call. It
+	// runs on the flushing goroutine, which waits for it.
+	OnError func(err error, failed []*Hbase.BatchMutation)
+}
+
+// flush is a buffer handed to the writer.
+type flush struct {
+	batches []*Hbase.BatchMutation
+	size    int
+}
+
+// BufferedMutator coalesces mutations into batches written with MutateRows
+// on a background goroutine, once a size, row count or time threshold is
+// hit. The mutations of one row given before a flush are sent as a single
+// BatchMutation. As the server applies the puts of a MutateRows call before
+// its deletes, a put and a delete of the same column given by different
+// Mutate calls are never buffered together: the buffer is handed off first,
+// so they apply in the order given. It is safe for concurrent use.
+//
+//	m := hbase.NewBufferedMutator(client, "table", hbase.BufferedMutatorConfig{})
+//	for _, r := range records {
+//		if err := m.Mutate(r.key, hbase.NewMutation("cf:v", r.value)); err != nil {
+//			return err
+//		}
+//	}
+//	return m.Close()
+type BufferedMutator struct {
+	client    *HClient
+	tableName string
+	cfg       BufferedMutatorConfig
+
+	mu      sync.Mutex
+	cond    *sync.Cond // broadcast when a flush is written or on Close
+	buf     flush
+	rows    map[string]*Hbase.BatchMutation // rows of buf
+	queue   []flush
+	pending int    // bytes buffered, queued or being written
+	queued  uint64 // flushes handed to the writer
+	written uint64 // flushes written
+	err     error  // first error since the last Flush
+	closed  bool
+	wake    chan struct{} // signals the writer, closed by Close
+	done    chan struct{} // closed when the writer returns
+}
+
+// NewBufferedMutator return a BufferedMutator writing to tableName with
+// client. Close must be called to write the last mutations and stop its
+// goroutine.
+func NewBufferedMutator(client *HClient, tableName string, cfg BufferedMutatorConfig) *BufferedMutator {
+	if cfg.FlushBytes <= 0 {
+		cfg.FlushBytes = 2 << 20
+	}
+	if cfg.FlushRows <= 0 {
+		cfg.FlushRows = 1000
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.MaxPendingBytes <= 0 {
+		cfg.MaxPendingBytes = 4 * cfg.FlushBytes
+	} else if cfg.MaxPendingBytes < cfg.FlushBytes {
+		cfg.MaxPendingBytes = cfg.FlushBytes
+	}
+	m := &BufferedMutator{
+		client:    client,
+		tableName: tableName,
+		cfg:       cfg,
+		rows:      make(map[string]*Hbase.BatchMutation),
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	m.cond = sync.NewCond(&m.mu)
+	go m.writer()
+	return m
+}
+
+// mutationSize estimates the bytes mutations of row take on the wire.
+func mutationSize(row []byte, mutations []*Hbase.Mutation) int {
+	size := len(row) + 16
+	for _, mutation := range mutations {
+		size += len(mutation.Column) + len(mutation.Value) + 16
+	}
+	return size
+}
+
+// Mutate buffers mutations of row. It blocks while MaxPendingBytes are
+// buffered or being written, a single call larger than that being allowed
+// once nothing else is pending.
+func (m *BufferedMutator) Mutate(row []byte, mutations ...*Hbase.Mutation) error {
+	if len(mutations) == 0 {
+		return nil
+	}
+	size := mutationSize(row, mutations)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for !m.closed && m.pending > 0 && m.pending+size > m.cfg.MaxPendingBytes {
+		// the pending bytes only go down once the buffer is written
+		m.handOff()
+		m.cond.Wait()
+	}
+	if m.closed {
+		return ErrMutatorClosed
+	}
+	if batch, ok := m.rows[string(row)]; ok && reorders(batch.Mutations, mutations) {
+		m.handOff()
+	}
+	if batch, ok := m.rows[string(row)]; ok {
+		batch.Mutations = append(batch.Mutations, mutations...)
+	} else {
+		batch = NewBatchMutation(row, append([]*Hbase.Mutation(nil), mutations...))
+		m.rows[string(row)] = batch
+		m.buf.batches = append(m.buf.batches, batch)
+	}
+	m.buf.size += size
+	m.pending += size
+	if m.buf.size >= m.cfg.FlushBytes || len(m.buf.batches) >= m.cfg.FlushRows {
+		m.handOff()
+	}
+	return nil
+}
+
+// reorders reports whether mutations put a column that pending deletes, or
+// delete one it puts, a delete of a family covering its columns.
+func reorders(pending, mutations []*Hbase.Mutation) bool {
+	for _, p := range pending {
+		for _, n := range mutations {
+			if p.IsDelete != n.IsDelete && sameColumn(p.Column, n.Column) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameColumn reports whether columns a and b overlap, "cf" and "cf:" naming
+// every column of family cf.
+func sameColumn(a, b []byte) bool {
+	familyA, qualifierA, _ := strings.Cut(string(a), ":")
+	familyB, qualifierB, _ := strings.Cut(string(b), ":")
+	if familyA != familyB {
+		return false
+	}
+	return qualifierA == "" || qualifierB == "" || qualifierA == qualifierB
+}
+
+// handOff queues the buffer for the writer, m.mu being held.
+func (m *BufferedMutator) handOff() {
+	if len(m.buf.batches) == 0 {
+		return
+	}
+	m.queue = append(m.queue, m.buf)
+	m.queued++
+	m.buf = flush{}
+	m.rows = make(map[string]*Hbase.BatchMutation)
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Flush writes every mutation given so far and return the first error met
+// by the writes since the previous Flush.
+func (m *BufferedMutator) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrMutatorClosed
+	}
+	m.handOff()
+	target := m.queued
+	for m.written < target {
+		m.cond.Wait()
+	}
+	err := m.err
+	m.err = nil
+	return err
+}
+
+// Close writes the buffered mutations, stops the writer and return the
+// first error met since the previous Flush. Later calls return
+// ErrMutatorClosed.
+func (m *BufferedMutator) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return ErrMutatorClosed
+	}
+	m.handOff()
+	m.closed = true
+	m.cond.Broadcast() // blocked Mutate calls fail
+	m.mu.Unlock()
+	close(m.wake)
+	<-m.done
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	err := m.err
+	m.err = nil
+	return err
+}
+
+// writer writes the queued flushes until the mutator is closed.
+func (m *BufferedMutator) writer() {
+	defer close(m.done)
+	var tick <-chan time.Time
+	if m.cfg.FlushInterval > 0 {
+		ticker := time.NewTicker(m.cfg.FlushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		open := true
+		select {
+		case _, open = <-m.wake:
+		case <-tick:
+			m.mu.Lock()
+			m.handOff()
+			m.mu.Unlock()
+		}
+		m.drain()
+		if !open {
+			return
+		}
+	}
+}
+
+// drain writes the queue.
+func (m *BufferedMutator) drain() {
+	for {
+		m.mu.Lock()
+		if len(m.queue) == 0 {
+			m.mu.Unlock()
+			return
+		}
+		f := m.queue[0]
+		m.queue[0] = flush{}
+		m.queue = m.queue[1:]
+		m.mu.Unlock()
+
+		err := m.client.MutateRowsContext(context.Background(), m.tableName, f.batches, m.cfg.Attributes)
+		if err != nil && m.cfg.OnError != nil {
+			m.cfg.OnError(err, f.batches)
+		}
+
+		m.mu.Lock()
+		if err != nil && m.err == nil {
+			m.err = err
+		}
+		m.pending -= f.size
+		m.written++
+		m.cond.Broadcast()
+		m.mu.Unlock()
+	}
+}
@@ -0,0 +1,223 @@
+package hbase_test
+
+import (
+	"bytes"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/J-J-J/hbase"
+	"github.com/J-J-J/hbase/Hbase"
+	"github.com/J-J-J/hbase/hbasetest"
+)
+
+// newMutatorClient return a client on a hbasetest server with a table "t",
+// counting its mutateRows calls in writes, each made to last delay.
+func newMutatorClient(t *testing.T, delay time.Duration, writes *int32) *hbase.HClient {
+	t.Helper()
+	srv, err := hbasetest.NewServer(hbasetest.NewStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	client, err := srv.NewClient(hbase.WithObserver(hbase.ObserverFunc(func(info hbase.CallInfo) {
+		if info.Method == "mutateRows" {
+			atomic.AddInt32(writes, 1)
+			time.Sleep(delay)
+		}
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	if _, err := client.CreateTable("t", []*hbase.ColumnDescriptor{hbase.NewColumnDescriptorDefault("cf")}); err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// within fails the test when fn does not return within d.
+func within(t *testing.T, d time.Duration, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(d):
+		t.Fatal("blocked for", d)
+	}
+}
+
+func rowKey(i int) []byte {
+	return []byte(fmt.Sprintf("r%03d", i))
+}
+
+func countRows(t *testing.T, client *hbase.HClient, n int) int {
+	t.Helper()
+	found := 0
+	for i := 0; i < n; i++ {
+		rows, err := client.GetRow("t", rowKey(i), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found += len(rows)
+	}
+	return found
+}
+
+func TestBufferedMutatorBackPressure(t *testing.T) {
+	const delay = 20 * time.Millisecond
+	var writes int32
+	client := newMutatorClient(t, delay, &writes)
+	value := bytes.Repeat([]byte("v"), 500)
+	for _, cfg := range []hbase.BufferedMutatorConfig{
+		// every Mutate waits for the buffer holding the previous one
+		{FlushBytes: 1000, MaxPendingBytes: 1000, FlushInterval: -1},
+		// clamped to FlushBytes
+		{FlushBytes: 1000, MaxPendingBytes: 100, FlushInterval: -1},
+	} {
+		atomic.StoreInt32(&writes, 0)
+		m := hbase.NewBufferedMutator(client, "t", cfg)
+		start := time.Now()
+		within(t, 10*time.Second, func() {
+			for i := 0; i < 10; i++ {
+				if err := m.Mutate(rowKey(i), hbase.NewMutation("cf:a", value)); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+		if elapsed := time.Since(start); elapsed < 9*delay {
+			t.Errorf("%+v: 10 calls took %v, want them to wait for the writes", cfg, elapsed)
+		}
+		within(t, 10*time.Second, func() {
+			if err := m.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+		if n := atomic.LoadInt32(&writes); n != 10 {
+			t.Errorf("%+v: %d writes, want 10", cfg, n)
+		}
+		if n := countRows(t, client, 10); n != 10 {
+			t.Errorf("%+v: %d rows written, want 10", cfg, n)
+		}
+	}
+}
+
+// TestBufferedMutatorLargeMutate gives a mutation that does not fit next to
+// a buffer under FlushBytes, which must be written for it to proceed.
+func TestBufferedMutatorLargeMutate(t *testing.T) {
+	var writes int32
+	client := newMutatorClient(t, 0, &writes)
+	m := hbase.NewBufferedMutator(client, "t", hbase.BufferedMutatorConfig{FlushBytes: 8192, MaxPendingBytes: 8192, FlushInterval: -1})
+	within(t, 10*time.Second, func() {
+		m.Mutate(rowKey(0), hbase.NewMutation("cf:a", bytes.Repeat([]byte("v"), 4000)))
+		m.Mutate(rowKey(1), hbase.NewMutation("cf:a", bytes.Repeat([]byte("v"), 5000)))
+		if err := m.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	if n := countRows(t, client, 2); n != 2 {
+		t.Errorf("%d rows written, want 2", n)
+	}
+}
+
+func TestBufferedMutatorFlushClose(t *testing.T) {
+	var writes int32
+	client := newMutatorClient(t, 0, &writes)
+	m := hbase.NewBufferedMutator(client, "t", hbase.BufferedMutatorConfig{FlushInterval: -1})
+	for i := 0; i < 20; i++ {
+		if err := m.Mutate(rowKey(i), hbase.NewMutation("cf:a", []byte("v"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := countRows(t, client, 40); n != 0 {
+		t.Fatalf("%d rows written before Flush", n)
+	}
+	if err := m.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, client, 40); n != 20 {
+		t.Fatalf("%d rows written by Flush, want 20", n)
+	}
+	for i := 20; i < 40; i++ {
+		if err := m.Mutate(rowKey(i), hbase.NewMutation("cf:a", []byte("v"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, client, 40); n != 40 {
+		t.Fatalf("%d rows written by Close, want 40", n)
+	}
+	if n := atomic.LoadInt32(&writes); n != 2 {
+		t.Errorf("%d writes, want 2", n)
+	}
+	if err := m.Mutate(rowKey(0), hbase.NewMutation("cf:a", []byte("v"))); err != hbase.ErrMutatorClosed {
+		t.Errorf("Mutate after Close: %v", err)
+	}
+	if err := m.Flush(); err != hbase.ErrMutatorClosed {
+		t.Errorf("Flush after Close: %v", err)
+	}
+	if err := m.Close(); err != hbase.ErrMutatorClosed {
+		t.Errorf("Close after Close: %v", err)
+	}
+}
+
+func TestBufferedMutatorOrder(t *testing.T) {
+	var writes int32
+	client := newMutatorClient(t, 0, &writes)
+	del := func(column string) *Hbase.Mutation {
+		return &Hbase.Mutation{IsDelete: true, Column: []byte(column), WriteToWAL: true}
+	}
+	put := func(column, value string) *Hbase.Mutation {
+		return hbase.NewMutation(column, []byte(value))
+	}
+	tests := []struct {
+		name   string
+		before []*Hbase.Mutation // written first
+		calls  [][]*Hbase.Mutation
+		want   string // value of cf:a in the end, "" for none
+		writes int32
+	}{
+		{"delete then put", []*Hbase.Mutation{put("cf:a", "old")}, [][]*Hbase.Mutation{{del("cf:a")}, {put("cf:a", "new")}}, "new", 2},
+		{"family delete then put", []*Hbase.Mutation{put("cf:a", "old")}, [][]*Hbase.Mutation{{del("cf")}, {put("cf:a", "new")}}, "new", 2},
+		{"put then delete", nil, [][]*Hbase.Mutation{{put("cf:a", "new")}, {del("cf:a")}}, "", 2},
+		{"other column", []*Hbase.Mutation{put("cf:a", "old")}, [][]*Hbase.Mutation{{del("cf:b")}, {put("cf:a", "new")}}, "new", 1},
+		{"puts", nil, [][]*Hbase.Mutation{{put("cf:a", "1")}, {put("cf:a", "2")}}, "2", 1},
+	}
+	for i, tt := range tests {
+		row := rowKey(i)
+		if tt.before != nil {
+			if err := client.MutateRow("t", row, tt.before, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		atomic.StoreInt32(&writes, 0)
+		m := hbase.NewBufferedMutator(client, "t", hbase.BufferedMutatorConfig{FlushInterval: -1})
+		for _, mutations := range tt.calls {
+			m.Mutate(row, mutations...)
+		}
+		if err := m.Close(); err != nil {
+			t.Fatal(err)
+		}
+		cells, err := client.Get("t", row, "cf:a", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		if len(cells) > 0 {
+			got = string(cells[0].Value)
+		}
+		if got != tt.want {
+			t.Errorf("%s: cf:a = %q, want %q", tt.name, got, tt.want)
+		}
+		if n := atomic.LoadInt32(&writes); n != tt.writes {
+			t.Errorf("%s: %d writes, want %d", tt.name, n, tt.writes)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package hbase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/J-J-J/hbase/Hbase"
+	"github.com/J-J-J/hbase/thrift"
+)
+
+// CallInfo describes one attempt of a call to the gateway, a retried call
+// being reported once per attempt.
+type CallInfo struct {
+	Method  string // thrift method, as "getRowWithColumns"
+	Table   string // "" for the calls not on a single table
+	Addr    string // address of the gateway
+	Attempt int    // 1 for the first try
+
+	Start    time.Time
+	Duration time.Duration
+
+	// BytesSent and BytesReceived count the thrift messages of the call,
+	// without framing nor HTTP headers.
+	BytesSent     int64
+	BytesReceived int64
+	// Rows is the number of rows returned, of cells for the calls returning
+	// cells, as get or getVer.
+	Rows int
+
+	Err        error
+	ErrorClass string // ErrorClass(Err)
+}
+
+// Observer is notified of every call a client makes to the gateway. It is
+// called from the goroutine of the call with the client locked, so it must
+// return quickly and not use that client.
+type Observer interface {
+	ObserveCall(info CallInfo)
+}
+
+// ObserverFunc adapts a function to an Observer.
+type ObserverFunc func(info CallInfo)
+
+// ObserveCall calls f(info).
+func (f ObserverFunc) ObserveCall(info CallInfo) {
+	f(info)
+}
+
+// multiObserver notifies several observers in turn.
+type multiObserver []Observer
+
+func (m multiObserver) ObserveCall(info CallInfo) {
+	for _, o := range m {
+		o.ObserveCall(info)
+	}
+}
+
+// WithObserver reports every call of the client to o, see Metrics and
+// PrometheusObserver. Observers given several times are all notified.
+func WithObserver(o Observer) ClientOption {
+	return func(opts *clientOptions) {
+		opts.observers = append(opts.observers, o)
+	}
+}
+
+// ErrorClass return a short name for the kind of err, suitable as a metric
+// label:
+//
+//	""                   no error
+//	"canceled"           the context of the call was canceled
+//	"timeout"            ErrTimeout
+//	"table_not_found"    ErrTableNotFound
+//	"table_exists"       ErrTableExists
+//	"scanner_not_found"  ErrScannerNotFound
+//	"illegal_argument"   ErrIllegalArgument
+//	"application"        ErrApplication
+//	"transport_closed"   ErrTransportClosed
+//	"transport"          any other transport or protocol failure
+//	"retryable"          an IOError after which the call may succeed, see IsRetryable
+//	"io"                 any other IOError
+//	"other"              anything else
+func ErrorClass(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, ErrTableNotFound):
+		return "table_not_found"
+	case errors.Is(err, ErrTableExists):
+		return "table_exists"
+	case errors.Is(err, ErrScannerNotFound):
+		return "scanner_not_found"
+	case errors.Is(err, ErrIllegalArgument):
+		return "illegal_argument"
+	case errors.Is(err, ErrApplication):
+		return "application"
+	case errors.Is(err, ErrTransportClosed):
+		return "transport_closed"
+	case isConnError(err):
+		return "transport"
+	}
+	var e *Error
+	if errors.As(err, &e) && e.IOErr != nil {
+		if IsRetryable(err) {
+			return "retryable"
+		}
+		return "io"
+	}
+	return "other"
+}
+
+// countingTransport counts the bytes the protocol writes and reads.
+type countingTransport struct {
+	thrift.TTransport
+	sent, received int64
+}
+
+func (t *countingTransport) Read(buf []byte) (int, error) {
+	n, err := t.TTransport.Read(buf)
+	t.received += int64(n)
+	return n, err
+}
+
+func (t *countingTransport) ReadAll(buf []byte) (int, error) {
+	n, err := t.TTransport.ReadAll(buf)
+	t.received += int64(n)
+	return n, err
+}
+
+func (t *countingTransport) Write(buf []byte) (int, error) {
+	n, err := t.TTransport.Write(buf)
+	t.sent += int64(n)
+	return n, err
+}
+
+// observe reports an attempt of method on table that started at start.
+func (client *HClient) observe(method, table string, attempt int, start time.Time, err error) {
+	info := CallInfo{
+		Method:     method,
+		Table:      table,
+		Addr:       client.addr,
+		Attempt:    attempt,
+		Start:      start,
+		Duration:   time.Since(start),
+		Rows:       client.rows,
+		Err:        err,
+		ErrorClass: ErrorClass(err),
+	}
+	if client.counter != nil {
+		info.BytesSent = client.counter.sent
+		info.BytesReceived = client.counter.received
+	}
+	client.observer.ObserveCall(info)
+}
+
+// resetCounters starts the counts of an attempt.
+func (client *HClient) resetCounters() {
+	client.rows = 0
+	if client.counter != nil {
+		client.counter.sent, client.counter.received = 0, 0
+	}
+}
+
+// returned records the number of rows or cells a call returned.
+func (client *HClient) returned(n int) {
+	client.rows = n
+}
+
+// openedScanner remembers the table of scanner id, for the observer of the
+// scanner calls that only know the id.
+func (client *HClient) openedScanner(id int32, tableName string) {
+	if client.observer != nil {
+		client.scanners.Store(id, tableName)
+	}
+}
+
+func (client *HClient) closedScanner(id int32) {
+	if client.observer != nil {
+		client.scanners.Delete(id)
+	}
+}
+
+// scannerTable return the table scanner id was opened on, "" when unknown.
+func (client *HClient) scannerTable(id int32) string {
+	if client.observer == nil {
+		return ""
+	}
+	table, _ := client.scanners.Load(id)
+	s, _ := table.(string)
+	return s
+}
+
+// incrementsTable return the table of increments, "" when they are on
+// several tables.
+func incrementsTable(increments []*Hbase.TIncrement) string {
+	if len(increments) == 0 {
+		return ""
+	}
+	table := string(increments[0].Table)
+	for _, inc := range increments[1:] {
+		if string(inc.Table) != table {
+			return ""
+		}
+	}
+	return table
+}
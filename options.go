@@ -0,0 +1,142 @@
+package hbase
+
+import (
+	"net/http"
+
+	"github.com/J-J-J/hbase/Hbase"
+	"github.com/J-J-J/hbase/thrift"
+)
+
+// ClientOption configures how a client builds its transport and protocol.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	buffered     bool
+	bufferSize   int
+	framed       bool
+	maxFrameSize int
+	compact      bool
+	retry        *RetryPolicy
+	validate     bool // parse filter strings before opening scanners
+	observers    []Observer
+
+	httpClient  *http.Client
+	httpHeader  http.Header
+	httpCookies []*http.Cookie
+	basicAuth   []string // username, password
+}
+
+func newClientOptions(buffered bool, opts []ClientOption) *clientOptions {
+	o := &clientOptions{buffered: buffered}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithFramedTransport sends every message as a length prefixed frame. It is
+// required by thrift gateways started with -nonblocking, -hsha or
+// -threadedselector. maxFrameSize <= 0 uses thrift.DEFAULT_MAX_FRAME_SIZE.
+func WithFramedTransport(maxFrameSize int) ClientOption {
+	return func(o *clientOptions) {
+		o.framed = true
+		o.maxFrameSize = maxFrameSize
+	}
+}
+
+// WithBufferSize sets the buffer size used by a buffered client, <= 0 uses
+// thrift.DEFAULT_BUFFER_SIZE.
+func WithBufferSize(size int) ClientOption {
+	return func(o *clientOptions) {
+		o.bufferSize = size
+	}
+}
+
+// WithCompactProtocol speaks the thrift compact protocol, for gateways
+// started with -compact.
+func WithCompactProtocol() ClientOption {
+	return func(o *clientOptions) {
+		o.compact = true
+	}
+}
+
+// WithFilterValidation parses the FilterString of every scan with
+// filter.Parse before opening the scanner, so that a malformed filter
+// fails with a *filter.SyntaxError instead of a server round trip.
+func WithFilterValidation() ClientOption {
+	return func(o *clientOptions) {
+		o.validate = true
+	}
+}
+
+// WithHTTPClient sets the http.Client used by an HTTP client, for custom
+// transports, proxies, TLS settings or a cookie Jar.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.httpClient = client
+	}
+}
+
+// WithHTTPHeader adds a header to every request of an HTTP client.
+func WithHTTPHeader(key, value string) ClientOption {
+	return func(o *clientOptions) {
+		if o.httpHeader == nil {
+			o.httpHeader = http.Header{}
+		}
+		o.httpHeader.Add(key, value)
+	}
+}
+
+// WithHTTPCookie adds a cookie to every request of an HTTP client.
+func WithHTTPCookie(cookie *http.Cookie) ClientOption {
+	return func(o *clientOptions) {
+		o.httpCookies = append(o.httpCookies, cookie)
+	}
+}
+
+// WithBasicAuth sends HTTP basic authentication with every request of an
+// HTTP client.
+func WithBasicAuth(username, password string) ClientOption {
+	return func(o *clientOptions) {
+		o.basicAuth = []string{username, password}
+	}
+}
+
+// protocolFactory return the protocol selected by the options.
+func (o *clientOptions) protocolFactory(trans thrift.TTransport) thrift.TProtocolFactory {
+	if o.compact {
+		return thrift.NewTCompactProtocol(trans)
+	}
+	return thrift.NewTBinaryProtocol(trans, false, true)
+}
+
+// newClient stacks the configured transports on top of trans.
+func newClient(addr string, trans thrift.TTransport, o *clientOptions) *HClient {
+	if o.buffered {
+		trans = thrift.NewTBufferedTransport(trans, o.bufferSize)
+	}
+	if o.framed {
+		trans = thrift.NewTFramedTransportMaxLength(trans, o.maxFrameSize)
+	}
+	client := &HClient{
+		addr:     addr,
+		retry:    o.retry,
+		validate: o.validate,
+		Trans:    trans,
+	}
+	switch len(o.observers) {
+	case 0:
+	case 1:
+		client.observer = o.observers[0]
+	default:
+		client.observer = multiObserver(o.observers)
+	}
+	if client.observer != nil {
+		// the protocol counts through the wrapper, client.Trans keeps the
+		// deadline of the transport
+		client.counter = &countingTransport{TTransport: trans}
+		trans = client.counter
+	}
+	client.hbase = Hbase.NewHbaseClientFactory(trans, o.protocolFactory(trans))
+	return client
+}
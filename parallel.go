@@ -0,0 +1,222 @@
+package hbase
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/J-J-J/hbase/Hbase"
+)
+
+// ParallelScanOptions configures a region-parallel scan.
+type ParallelScanOptions struct {
+	// Concurrency caps the number of regions scanned at once, zero uses 4.
+	// Every region being scanned holds a client of the pool, so a pool with
+	// fewer clients scans fewer regions at once.
+	Concurrency int
+	// Ordered delivers the rows in key order. Regions are then delivered one
+	// after the other, and a region done early waits with one batch buffered
+	// and its scanner open until the regions before it are delivered, so the
+	// scanner lease period must cover the time it takes to scan a region.
+	Ordered bool
+}
+
+// regionRange is the part of a scan that falls into one region.
+type regionRange struct {
+	start, stop []byte
+}
+
+// ParallelScan runs scan over tableName with one scanner per region,
+// regions being scanned concurrently on clients borrowed from the pool. The
+// range of scan is intersected with every region, its columns, timestamp,
+// caching and filter apply to every region. Every region is read with a
+// ResumableScan, which reopens after the last row when its scanner is lost.
+// fn is called for every row, never concurrently. The first error returned
+// by fn or by a scanner cancels the remaining regions and is returned.
+func (p *HClientPool) ParallelScan(ctx context.Context, tableName string, scan *TScan, attributes map[string]string, opts ParallelScanOptions, fn func(row *Hbase.TRowResult) error) error {
+	if scan == nil {
+		scan = &TScan{}
+	}
+	var regions []*TRegionInfo
+	err := p.DoContext(ctx, func(client *HClient) (err error) {
+		regions, err = client.GetTableRegionsContext(ctx, tableName)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	ranges := regionRanges(regions, scan.StartRow, scan.StopRow)
+	if len(ranges) == 0 {
+		return nil
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	batch := int(scan.Caching)
+	if batch <= 0 {
+		batch = DefaultScanBatch
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	// every region sends to its own channel when ordered, to a shared one
+	// otherwise
+	outs := make([]chan *Hbase.TRowResult, len(ranges))
+	if opts.Ordered {
+		for i := range outs {
+			outs[i] = make(chan *Hbase.TRowResult, batch)
+		}
+	} else {
+		shared := make(chan *Hbase.TRowResult, batch)
+		for i := range outs {
+			outs[i] = shared
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	go func() {
+		if !opts.Ordered {
+			defer func() {
+				wg.Wait()
+				close(outs[0])
+			}()
+		}
+		// regions borrow their client then take their slot in key order, so
+		// in ordered mode the region being delivered always holds both, even
+		// when the pool has fewer clients than the concurrency
+		for i, r := range ranges {
+			client, err := p.GetContext(ctx)
+			if err == nil {
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					p.Put(client)
+					err = ctx.Err()
+				}
+			}
+			if err != nil {
+				fail(err)
+				if opts.Ordered {
+					for _, out := range outs[i:] {
+						close(out)
+					}
+				}
+				return
+			}
+			wg.Add(1)
+			go func(client *HClient, r regionRange, out chan *Hbase.TRowResult) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if opts.Ordered {
+					defer close(out)
+				}
+				regionScan := *scan
+				regionScan.StartRow, regionScan.StopRow = r.start, r.stop
+				err := scanRegion(ctx, client, tableName, &regionScan, attributes, out)
+				p.giveBack(client, err)
+				if err != nil {
+					fail(err)
+				}
+			}(client, r, outs[i])
+		}
+	}()
+
+	deliver := func(out chan *Hbase.TRowResult) {
+		for row := range out {
+			if ctx.Err() != nil {
+				continue // drain so the senders finish
+			}
+			if err := fn(row); err != nil {
+				fail(err)
+			}
+		}
+	}
+	if opts.Ordered {
+		for _, out := range outs {
+			deliver(out)
+		}
+	} else {
+		deliver(outs[0])
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// ParallelScanChan is ParallelScan delivering the rows on a channel. The
+// row channel is closed at the end of the scan, errc then receives its
+// error, nil on success. The caller must read the rows until the channel
+// is closed or cancel ctx.
+func (p *HClientPool) ParallelScanChan(ctx context.Context, tableName string, scan *TScan, attributes map[string]string, opts ParallelScanOptions) (<-chan *Hbase.TRowResult, <-chan error) {
+	rows := make(chan *Hbase.TRowResult)
+	errc := make(chan error, 1)
+	go func() {
+		err := p.ParallelScan(ctx, tableName, scan, attributes, opts, func(row *Hbase.TRowResult) error {
+			select {
+			case rows <- row:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(rows)
+		errc <- err
+	}()
+	return rows, errc
+}
+
+// scanRegion sends the rows of one region to out.
+func scanRegion(ctx context.Context, client *HClient, tableName string, scan *TScan, attributes map[string]string, out chan<- *Hbase.TRowResult) error {
+	sc, err := client.ResumableScanContext(ctx, tableName, scan, attributes)
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+	for sc.Next() {
+		select {
+		case out <- sc.Row():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return sc.Err()
+}
+
+// regionRanges intersects [start, stop) with every region, an empty key
+// being unbounded, and return the non-empty parts in key order.
+func regionRanges(regions []*TRegionInfo, start, stop []byte) []regionRange {
+	sorted := make([]*TRegionInfo, len(regions))
+	copy(sorted, regions)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartKey < sorted[j].StartKey })
+
+	var ranges []regionRange
+	for _, region := range sorted {
+		rstart, rstop := []byte(region.StartKey), []byte(region.EndKey)
+		if string(start) > string(rstart) {
+			rstart = start
+		}
+		if len(stop) > 0 && (len(rstop) == 0 || string(stop) < string(rstop)) {
+			rstop = stop
+		}
+		if len(rstop) > 0 && string(rstart) >= string(rstop) {
+			continue
+		}
+		ranges = append(ranges, regionRange{start: rstart, stop: rstop})
+	}
+	return ranges
+}
@@ -0,0 +1,465 @@
+package hbase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// pool errors
+var (
+	ErrPoolClosed  = errors.New("hbase: client pool is closed")
+	ErrPoolTimeout = errors.New("hbase: timed out waiting for a pooled client")
+)
+
+// PoolConfig configures an HClientPool.
+type PoolConfig struct {
+	// Dial creates a new, not yet opened, client. It is required.
+	Dial func() (*HClient, error)
+	// MinConns is the number of connections kept open even when idle.
+	MinConns int
+	// MaxConns caps the number of open connections, zero means unlimited.
+	MaxConns int
+	// IdleTimeout closes connections that stay idle longer than this.
+	IdleTimeout time.Duration
+	// MaxLifetime closes connections older than this once they are returned.
+	MaxLifetime time.Duration
+	// WaitTimeout bounds how long Get blocks when MaxConns is reached,
+	// zero means wait forever.
+	WaitTimeout time.Duration
+	// Validate is called on every borrowed idle connection, a non-nil error
+	// discards it and another one is tried.
+	Validate func(client *HClient) error
+}
+
+// PoolStats describe the current state of a pool.
+type PoolStats struct {
+	Open    int // open connections, idle and in use
+	Idle    int // idle connections
+	Waiters int // goroutines blocked in Get
+}
+
+type poolConn struct {
+	client   *HClient
+	created  time.Time
+	returned time.Time
+}
+
+// HClientPool is a goroutine-safe pool of HClient connections. An HClient
+// serializes its calls on one connection, the pool hands each one to a
+// single goroutine at a time so calls run in parallel.
+type HClientPool struct {
+	cfg PoolConfig
+
+	mu      sync.Mutex
+	idle    []*poolConn
+	active  map[*HClient]*poolConn
+	numOpen int
+	waiters []chan *poolConn // a nil send means a slot was freed
+	closed  bool
+	stop    chan struct{}
+}
+
+// NewHClientPool return a pool that dials its connections with cfg.Dial.
+func NewHClientPool(cfg PoolConfig) (*HClientPool, error) {
+	if cfg.Dial == nil {
+		return nil, errors.New("hbase: PoolConfig.Dial is required")
+	}
+	if cfg.MaxConns > 0 && cfg.MinConns > cfg.MaxConns {
+		cfg.MinConns = cfg.MaxConns
+	}
+	p := &HClientPool{
+		cfg:    cfg,
+		active: make(map[*HClient]*poolConn),
+		stop:   make(chan struct{}),
+	}
+	p.fill()
+	if interval := p.cleanInterval(); interval > 0 {
+		go p.janitor(interval)
+	}
+	return p, nil
+}
+
+// NewTCPClientPool return a pool of tcp clients connected to rawaddr.
+func NewTCPClientPool(rawaddr string, buffered bool, cfg PoolConfig, opts ...ClientOption) (*HClientPool, error) {
+	cfg.Dial = func() (*HClient, error) {
+		return NewTCPClient(rawaddr, buffered, opts...)
+	}
+	return NewHClientPool(cfg)
+}
+
+// Do borrows a client, calls fn with it and returns it to the pool. The
+// client is discarded instead when fn fails with a transport error, so a
+// half-read connection is never reused.
+func (p *HClientPool) Do(fn func(client *HClient) error) error {
+	return p.DoContext(context.Background(), fn)
+}
+
+// DoContext is Do with a context bounding the wait for a free client.
+// fn should pass ctx on to the client calls it makes.
+func (p *HClientPool) DoContext(ctx context.Context, fn func(client *HClient) error) error {
+	client, err := p.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	err = fn(client)
+	p.giveBack(client, err)
+	return err
+}
+
+// giveBack returns a client after a call that failed with err to the pool,
+// or discards it when err left its connection in an unknown state. A call
+// whose context ended before it reached the wire keeps its connection open.
+func (p *HClientPool) giveBack(client *HClient, err error) {
+	canceled := errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+	if isConnError(err) && !(canceled && client.isOpen()) {
+		p.Discard(client)
+		return
+	}
+	p.Put(client)
+}
+
+// Get borrows an open client. It must be given back with Put or Discard.
+func (p *HClientPool) Get() (*HClient, error) {
+	return p.GetContext(context.Background())
+}
+
+// GetContext is Get with a context bounding the wait for a free client.
+func (p *HClientPool) GetContext(ctx context.Context) (*HClient, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var timeout <-chan time.Time
+	if p.cfg.WaitTimeout > 0 {
+		t := time.NewTimer(p.cfg.WaitTimeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrPoolClosed
+		}
+		if pc := p.popIdle(); pc != nil {
+			p.mu.Unlock()
+			if p.healthy(pc) {
+				p.checkout(pc)
+				return pc.client, nil
+			}
+			p.closeConn(pc)
+			continue
+		}
+		if p.cfg.MaxConns <= 0 || p.numOpen < p.cfg.MaxConns {
+			p.numOpen++
+			p.mu.Unlock()
+			return p.dialActive()
+		}
+
+		ch := make(chan *poolConn, 1)
+		p.waiters = append(p.waiters, ch)
+		p.mu.Unlock()
+
+		select {
+		case pc, ok := <-ch:
+			if !ok {
+				return nil, ErrPoolClosed
+			}
+			if pc == nil {
+				// a slot was handed over, dial a new connection in it
+				return p.dialActive()
+			}
+			p.checkout(pc)
+			return pc.client, nil
+		case <-timeout:
+			p.abandon(ch)
+			return nil, ErrPoolTimeout
+		case <-ctx.Done():
+			p.abandon(ch)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// abandon dequeues a waiter that gave up. A hand-off that raced with it is
+// passed on.
+func (p *HClientPool) abandon(ch chan *poolConn) {
+	p.mu.Lock()
+	removed := p.removeWaiter(ch)
+	p.mu.Unlock()
+	if removed {
+		return
+	}
+	if pc, ok := <-ch; ok {
+		if pc == nil {
+			p.freeSlot()
+		} else {
+			p.release(pc)
+		}
+	}
+}
+
+// Put returns a healthy client to the pool.
+func (p *HClientPool) Put(client *HClient) {
+	p.mu.Lock()
+	pc, ok := p.active[client]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.active, client)
+	p.mu.Unlock()
+
+	pc.returned = time.Now()
+	if p.expired(pc, pc.returned) {
+		p.closeConn(pc)
+		return
+	}
+	p.release(pc)
+}
+
+// Discard closes a borrowed client instead of returning it to the pool.
+func (p *HClientPool) Discard(client *HClient) {
+	p.mu.Lock()
+	pc, ok := p.active[client]
+	if ok {
+		delete(p.active, client)
+	}
+	p.mu.Unlock()
+	if ok {
+		p.closeConn(pc)
+	}
+}
+
+// Stats return a snapshot of the pool state.
+func (p *HClientPool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return PoolStats{
+		Open:    p.numOpen,
+		Idle:    len(p.idle),
+		Waiters: len(p.waiters),
+	}
+}
+
+// Close closes all idle clients and fails pending and future Gets.
+// Borrowed clients are closed when they are returned.
+func (p *HClientPool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.stop)
+	idle := p.idle
+	p.idle = nil
+	for _, ch := range p.waiters {
+		close(ch)
+	}
+	p.waiters = nil
+	p.mu.Unlock()
+
+	var err error
+	for _, pc := range idle {
+		if e := p.closeConn(pc); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// release hands pc to a waiter or parks it in the idle list.
+func (p *HClientPool) release(pc *poolConn) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		p.closeConn(pc)
+		return
+	}
+	if len(p.waiters) > 0 {
+		ch := p.waiters[0]
+		p.waiters = p.waiters[1:]
+		p.mu.Unlock()
+		ch <- pc
+		return
+	}
+	p.idle = append(p.idle, pc)
+	p.mu.Unlock()
+}
+
+// closeConn closes pc and frees its slot.
+func (p *HClientPool) closeConn(pc *poolConn) error {
+	err := pc.client.Close()
+	p.freeSlot()
+	return err
+}
+
+// freeSlot gives an open slot back, waking one waiter to dial in it.
+func (p *HClientPool) freeSlot() {
+	p.mu.Lock()
+	if len(p.waiters) > 0 {
+		ch := p.waiters[0]
+		p.waiters = p.waiters[1:]
+		p.mu.Unlock()
+		ch <- nil
+		return
+	}
+	p.numOpen--
+	p.mu.Unlock()
+}
+
+// dialActive opens a connection in an already reserved slot.
+func (p *HClientPool) dialActive() (*HClient, error) {
+	pc, err := p.dial()
+	if err != nil {
+		p.freeSlot()
+		return nil, err
+	}
+	p.checkout(pc)
+	return pc.client, nil
+}
+
+func (p *HClientPool) dial() (*poolConn, error) {
+	client, err := p.cfg.Dial()
+	if err != nil {
+		return nil, err
+	}
+	if err = client.Open(); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &poolConn{client: client, created: now, returned: now}, nil
+}
+
+func (p *HClientPool) checkout(pc *poolConn) {
+	p.mu.Lock()
+	p.active[pc.client] = pc
+	p.mu.Unlock()
+}
+
+// popIdle takes the most recently returned connection, caller holds p.mu.
+func (p *HClientPool) popIdle() *poolConn {
+	n := len(p.idle)
+	if n == 0 {
+		return nil
+	}
+	pc := p.idle[n-1]
+	p.idle[n-1] = nil
+	p.idle = p.idle[:n-1]
+	return pc
+}
+
+// removeWaiter reports whether ch was still queued, caller holds p.mu.
+func (p *HClientPool) removeWaiter(ch chan *poolConn) bool {
+	for i, w := range p.waiters {
+		if w == ch {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (p *HClientPool) expired(pc *poolConn, now time.Time) bool {
+	if p.cfg.MaxLifetime > 0 && now.Sub(pc.created) > p.cfg.MaxLifetime {
+		return true
+	}
+	if p.cfg.IdleTimeout > 0 && now.Sub(pc.returned) > p.cfg.IdleTimeout {
+		return true
+	}
+	return false
+}
+
+func (p *HClientPool) healthy(pc *poolConn) bool {
+	if p.expired(pc, time.Now()) {
+		return false
+	}
+	if !pc.client.isOpen() {
+		return false
+	}
+	if pc.client.gw != nil && pc.client.gw.ejected(time.Now()) {
+		return false
+	}
+	if p.cfg.Validate != nil && p.cfg.Validate(pc.client) != nil {
+		return false
+	}
+	return true
+}
+
+func (p *HClientPool) cleanInterval() time.Duration {
+	var d time.Duration
+	for _, t := range []time.Duration{p.cfg.IdleTimeout, p.cfg.MaxLifetime} {
+		if t > 0 && (d == 0 || t < d) {
+			d = t
+		}
+	}
+	if d == 0 && p.cfg.MinConns > 0 {
+		d = time.Minute
+	}
+	if d > 0 && d/2 < time.Second {
+		return time.Second
+	}
+	return d / 2
+}
+
+func (p *HClientPool) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.evict()
+			p.fill()
+		}
+	}
+}
+
+// evict closes idle connections past their idle timeout or lifetime.
+func (p *HClientPool) evict() {
+	now := time.Now()
+	var stale []*poolConn
+	p.mu.Lock()
+	kept := p.idle[:0]
+	for _, pc := range p.idle {
+		if p.expired(pc, now) {
+			stale = append(stale, pc)
+		} else {
+			kept = append(kept, pc)
+		}
+	}
+	for i := len(kept); i < len(p.idle); i++ {
+		p.idle[i] = nil
+	}
+	p.idle = kept
+	p.mu.Unlock()
+
+	for _, pc := range stale {
+		p.closeConn(pc)
+	}
+}
+
+// fill opens connections until MinConns are open. Dial errors are left for
+// the next Get to report.
+func (p *HClientPool) fill() {
+	for {
+		p.mu.Lock()
+		if p.closed || p.numOpen >= p.cfg.MinConns {
+			p.mu.Unlock()
+			return
+		}
+		p.numOpen++
+		p.mu.Unlock()
+
+		pc, err := p.dial()
+		if err != nil {
+			p.freeSlot()
+			return
+		}
+		p.release(pc)
+	}
+}
@@ -0,0 +1,205 @@
+package hbase_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/J-J-J/hbase"
+	"github.com/J-J-J/hbase/hbasetest"
+)
+
+// newTestPool return a pool of clients of a hbasetest server, counting the
+// connections dialed in dials.
+func newTestPool(t *testing.T, cfg hbase.PoolConfig, dials *int32) *hbase.HClientPool {
+	t.Helper()
+	srv, err := hbasetest.NewServer(hbasetest.NewStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	cfg.Dial = func() (*hbase.HClient, error) {
+		atomic.AddInt32(dials, 1)
+		return srv.NewClient()
+	}
+	p, err := hbase.NewHClientPool(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+// waitFor polls cond for up to a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPoolCanceledCallKeepsClient(t *testing.T) {
+	var dials int32
+	p := newTestPool(t, hbase.PoolConfig{MaxConns: 1}, &dials)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 10; i++ {
+		// the wait for a client is not bounded by ctx, the call is
+		err := p.Do(func(client *hbase.HClient) error {
+			_, err := client.GetTableNamesContext(ctx)
+			return err
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want context.Canceled", err)
+		}
+	}
+	if err := p.Do(func(client *hbase.HClient) error {
+		_, err := client.GetTableNames()
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if dials != 1 {
+		t.Errorf("%d connections dialed, want 1", dials)
+	}
+	if st := p.Stats(); st.Open != 1 || st.Idle != 1 {
+		t.Errorf("stats %+v, want one idle connection", st)
+	}
+}
+
+func TestPoolWaiters(t *testing.T) {
+	var dials int32
+	p := newTestPool(t, hbase.PoolConfig{MaxConns: 1, WaitTimeout: 50 * time.Millisecond}, &dials)
+	c1, err := p.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a waiter gets the client put back
+	got := make(chan *hbase.HClient)
+	go func() {
+		c, err := p.GetContext(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		got <- c
+	}()
+	waitFor(t, "a waiter", func() bool { return p.Stats().Waiters == 1 })
+	p.Put(c1)
+	if c := <-got; c != c1 {
+		t.Fatal("the waiter did not get the client put back")
+	}
+
+	// waiters giving up are dequeued
+	if _, err := p.Get(); err != hbase.ErrPoolTimeout {
+		t.Fatalf("got %v, want ErrPoolTimeout", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.GetContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+	if st := p.Stats(); st.Waiters != 0 || st.Open != 1 {
+		t.Fatalf("stats %+v after the waiters gave up", st)
+	}
+
+	// a discarded client frees its slot for a waiter, which dials
+	go func() {
+		c, err := p.GetContext(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		got <- c
+	}()
+	waitFor(t, "a waiter", func() bool { return p.Stats().Waiters == 1 })
+	p.Discard(c1)
+	c2 := <-got
+	if c2 == nil || c2 == c1 || dials != 2 {
+		t.Fatalf("the waiter got %p after discarding %p, %d dials", c2, c1, dials)
+	}
+
+	// Close fails the waiters
+	errc := make(chan error)
+	go func() {
+		_, err := p.GetContext(context.Background())
+		errc <- err
+	}()
+	waitFor(t, "a waiter", func() bool { return p.Stats().Waiters == 1 })
+	p.Close()
+	if err := <-errc; err != hbase.ErrPoolClosed {
+		t.Fatalf("got %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestPoolConcurrent(t *testing.T) {
+	var dials int32
+	p := newTestPool(t, hbase.PoolConfig{MaxConns: 1}, &dials)
+	var (
+		wg       sync.WaitGroup
+		ok       int32
+		timeouts int32
+	)
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 30; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g%4)*time.Millisecond)
+				if g%4 == 0 {
+					ctx, cancel = context.WithCancel(context.Background())
+				}
+				err := p.DoContext(ctx, func(client *hbase.HClient) error {
+					_, err := client.GetTableNamesContext(ctx)
+					return err
+				})
+				cancel()
+				switch {
+				case err == nil:
+					atomic.AddInt32(&ok, 1)
+				case errors.Is(err, context.DeadlineExceeded):
+					atomic.AddInt32(&timeouts, 1)
+				default:
+					t.Error(err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if ok == 0 {
+		t.Error("no call succeeded")
+	}
+	if st := p.Stats(); st.Open > 1 || st.Waiters != 0 || st.Open != st.Idle {
+		t.Errorf("stats %+v after the calls", st)
+	}
+	t.Logf("%d calls, %d timeouts, %d dials", ok, timeouts, dials)
+}
+
+func TestPoolJanitor(t *testing.T) {
+	var dials int32
+	p := newTestPool(t, hbase.PoolConfig{MinConns: 1, MaxConns: 2, IdleTimeout: 200 * time.Millisecond}, &dials)
+	if st := p.Stats(); st.Open != 1 || st.Idle != 1 {
+		t.Fatalf("stats %+v, want MinConns open", st)
+	}
+	c1, _ := p.Get()
+	c2, err := p.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Put(c1)
+	p.Put(c2)
+	if st := p.Stats(); st.Open != 2 || st.Idle != 2 {
+		t.Fatalf("stats %+v, want two idle connections", st)
+	}
+	// both expire, the janitor closes them and dials MinConns again
+	waitFor(t, "the janitor", func() bool { return atomic.LoadInt32(&dials) == 3 })
+	waitFor(t, "the janitor", func() bool {
+		st := p.Stats()
+		return st.Open == 1 && st.Idle == 1
+	})
+}
@@ -0,0 +1,163 @@
+package hbase
+
+import (
+	"context"
+	"errors"
+)
+
+// ScanCheckpoint is the position of a resumable scan. It may be stored, for
+// example as JSON, to resume the scan in another process with ResumeScan.
+type ScanCheckpoint struct {
+	Table      string
+	Scan       TScan // StartRow is just past LastRow
+	Attributes map[string]string
+	LastRow    []byte // last row returned, nil before the first one
+	Done       bool   // every row was returned
+}
+
+// scanResume is the state a resumable scanner reopens from.
+type scanResume struct {
+	tableName  string
+	scan       TScan
+	attributes map[string]string
+	lastRow    []byte
+	resumed    bool // reopened and no row returned since
+	done       bool
+}
+
+// ResumableScan opens a scanner on tableName that survives the loss of its
+// server side scanner, after a gateway restart or an expired lease. The
+// Scanner tracks the last row returned, and when a fetch fails with an
+// IllegalArgument, a transport error or an IOError naming an expired
+// scanner, it reopens the scan just past that row with the same columns,
+// filter, timestamp and caching and continues without duplicates. A scanner
+// reopened is given up if it fails again before returning a row. Filters
+// counting rows, such as PageFilter or WhileMatchFilter, count again from
+// the reopened position.
+//
+// Scanner fetches are never replayed by the RetryPolicy, a replayed
+// ScannerGetList would skip rows, a resumable scanner reopens instead.
+func (client *HClient) ResumableScan(tableName string, scan *TScan, attributes map[string]string) (*Scanner, error) {
+	return client.ResumableScanContext(context.Background(), tableName, scan, attributes)
+}
+
+// ResumableScanContext is ResumableScan with a context, ctx also bounds
+// every fetch of the Scanner.
+func (client *HClient) ResumableScanContext(ctx context.Context, tableName string, scan *TScan, attributes map[string]string) (*Scanner, error) {
+	if scan == nil {
+		scan = &TScan{}
+	}
+	r := &scanResume{tableName: tableName, scan: copyTScan(scan), attributes: attributes}
+	return client.openResumable(ctx, r)
+}
+
+// ResumeScan continues the scan saved in cp, usually by another process,
+// with a resumable scanner. The scanner of a done checkpoint has no rows.
+func (client *HClient) ResumeScan(cp *ScanCheckpoint) (*Scanner, error) {
+	return client.ResumeScanContext(context.Background(), cp)
+}
+
+// ResumeScanContext is ResumeScan with a context.
+func (client *HClient) ResumeScanContext(ctx context.Context, cp *ScanCheckpoint) (*Scanner, error) {
+	r := &scanResume{
+		tableName:  cp.Table,
+		scan:       copyTScan(&cp.Scan),
+		attributes: cp.Attributes,
+		lastRow:    cp.LastRow,
+		done:       cp.Done,
+	}
+	if r.done {
+		return &Scanner{client: client, ctx: ctx, closed: true, resume: r}, nil
+	}
+	return client.openResumable(ctx, r)
+}
+
+func (client *HClient) openResumable(ctx context.Context, r *scanResume) (*Scanner, error) {
+	scan := r.position()
+	id, err := client.ScannerOpenWithScanContext(ctx, r.tableName, &scan, r.attributes)
+	if err != nil {
+		return nil, err
+	}
+	s := NewScanner(ctx, client, id, scan.Caching)
+	s.resume = r
+	return s, nil
+}
+
+// Checkpoint return the position of a resumable scanner after the rows
+// returned so far by Next, nil for other scanners. Save it once these rows
+// are processed.
+func (s *Scanner) Checkpoint() *ScanCheckpoint {
+	r := s.resume
+	if r == nil {
+		return nil
+	}
+	attributes := make(map[string]string, len(r.attributes))
+	for k, v := range r.attributes {
+		attributes[k] = v
+	}
+	cp := &ScanCheckpoint{
+		Table:      r.tableName,
+		Scan:       r.position(),
+		Attributes: attributes,
+		Done:       r.done,
+	}
+	if r.lastRow != nil {
+		cp.LastRow = append([]byte(nil), r.lastRow...)
+	}
+	return cp
+}
+
+// advance records row as returned.
+func (r *scanResume) advance(row []byte) {
+	r.lastRow = row
+	r.resumed = false
+}
+
+// position return the scan starting just past the last row returned.
+func (r *scanResume) position() TScan {
+	scan := copyTScan(&r.scan)
+	if r.lastRow != nil {
+		scan.StartRow = append(append(make([]byte, 0, len(r.lastRow)+1), r.lastRow...), 0)
+	}
+	return scan
+}
+
+// reopen opens the scan again after cause ended the server side scanner,
+// and return nil when the scan goes on or the error ending it.
+func (s *Scanner) reopen(cause error) error {
+	r := s.resume
+	if r.resumed || s.ctx.Err() != nil || !isScannerLost(cause) {
+		return cause
+	}
+	if e, ok := cause.(*Error); ok && e.IOErr != nil {
+		// the gateway is alive and still holds the broken scanner
+		s.client.ScannerCloseContext(context.Background(), s.id)
+	}
+	// from here the old id is never closed, a restarted gateway may have
+	// given it to another scanner
+	s.closed = true
+	s.rows, s.row = nil, nil
+	scan := r.position()
+	id, err := s.client.ScannerOpenWithScanContext(s.ctx, r.tableName, &scan, r.attributes)
+	if err != nil {
+		return err
+	}
+	s.id, s.closed, s.pos = id, false, 0
+	r.resumed = true
+	s.prefetch()
+	return nil
+}
+
+// isScannerLost reports whether err may have ended a server side scanner.
+func isScannerLost(err error) bool {
+	return errors.Is(err, ErrScannerNotFound) || errors.Is(err, ErrIllegalArgument) || isConnError(err)
+}
+
+// copyTScan return a copy of scan not sharing its slices.
+func copyTScan(scan *TScan) TScan {
+	c := *scan
+	c.StartRow = append([]byte(nil), scan.StartRow...)
+	c.StopRow = append([]byte(nil), scan.StopRow...)
+	c.Columns = append([]string(nil), scan.Columns...)
+	return c
+}
@@ -0,0 +1,113 @@
+package hbase
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how calls failing in the transport, for example
+// while a gateway restarts, are retried. Each retry reopens the connection.
+// Only idempotent calls are replayed once their request may have reached the
+// server, other calls report the failure and leave the decision to the
+// caller.
+type RetryPolicy struct {
+	// MaxAttempts is the number of tries including the first one.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two tries.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every retry, values below 1 use 2.
+	Multiplier float64
+	// Jitter randomizes every wait by up to this fraction of it, in [0, 1].
+	Jitter float64
+}
+
+// DefaultRetryPolicy tries a call up to three times over roughly a second.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// WithRetryPolicy retries calls failing in the transport per policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retry = &policy
+	}
+}
+
+// idempotentMethods may be replayed after a transport failure. Scanner
+// fetches are not: a replayed scannerGet or scannerGetList would skip the
+// rows carried by the lost reply when the server did receive the first
+// request, ResumableScan reopens the scan after the last row instead.
+var idempotentMethods = map[string]bool{
+	"isTableEnabled":       true,
+	"getTableNames":        true,
+	"getColumnDescriptors": true,
+	"getTableRegions":      true,
+	"get":                  true,
+	"getVer":               true,
+	"getVerTs":             true,
+	"getRow":               true,
+	"getRowWithColumns":    true,
+	"getRowTs":             true,
+	"getRowWithColumnsTs":  true,
+	"getRows":              true,
+	"getRowsWithColumns":   true,
+	"getRowsTs":            true,
+	"getRowsWithColumnsTs": true,
+	"getRowOrBefore":       true,
+	"getRegionInfo":        true,
+}
+
+// replayable reports whether method may be replayed after its request may
+// have reached the server.
+func replayable(method string) bool {
+	return idempotentMethods[method]
+}
+
+// allows reports whether another try may follow the given attempt.
+func (p *RetryPolicy) allows(attempt int) bool {
+	return p != nil && attempt < p.MaxAttempts
+}
+
+// backoff return the wait after the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= multiplier
+		if p.MaxBackoff > 0 && d >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
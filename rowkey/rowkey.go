@@ -0,0 +1,324 @@
+// Package rowkey encodes tuples into row keys that sort like the tuples, in
+// the spirit of the OrderedBytes class of the Java client. Comparing two
+// encoded keys byte by byte compares their values one by one, signed
+// integers, floats and variable length strings included, so range scans
+// over a key prefix are correct by construction:
+//
+//	key, err := rowkey.Encode(tenant, rowkey.Desc{V: ts.UnixMilli()}, entityID)
+//	...
+//	scan, err := rowkey.PrefixScan(tenant)
+//	sc, err := client.Scan("events", scan, nil)
+//
+// Every value starts with a header byte naming its type, which makes keys
+// self-describing: Decode return the tuple back. A value in descending order
+// has all its bytes inverted, header included.
+//
+// Layouts, before inversion:
+//
+//	nil      0x05
+//	int64    0x2c, 8 bytes big-endian with the sign bit flipped
+//	uint64   0x2d, 8 bytes big-endian
+//	float32  0x30, 4 bytes, the IEEE bits of positive numbers with the sign
+//	         bit flipped, of negative ones all inverted
+//	float64  0x31, 8 bytes, as float32
+//	string   0x34, the bytes with 0x00 escaped as 0x00 0xff, then 0x00 0x01
+//	[]byte   0x37, as string
+//
+// nil sorts before any other value ascending, after any descending. Values
+// of different types sort by header.
+package rowkey
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// header bytes of the ascending encodings
+const (
+	headerNull    = 0x05
+	headerInt64   = 0x2c
+	headerUint64  = 0x2d
+	headerFloat32 = 0x30
+	headerFloat64 = 0x31
+	headerString  = 0x34
+	headerBytes   = 0x37
+)
+
+const (
+	escape     = 0x00
+	escaped    = 0xff // follows an escaped 0x00
+	terminator = 0x01 // follows 0x00 at the end of a string
+)
+
+// Order is the sort order of one value of a key.
+type Order int
+
+const (
+	Ascending Order = iota
+	Descending
+)
+
+// Desc wraps a value to encode in descending order, for example a timestamp
+// so that the latest entries come first. Decode return the values of
+// descending order wrapped in a Desc.
+type Desc struct {
+	V interface{}
+}
+
+// ErrCorrupt is returned when decoding an invalid key.
+var ErrCorrupt = errors.New("rowkey: invalid encoded key")
+
+// invert inverts the bytes of b from start when order is Descending.
+func invert(b []byte, start int, order Order) []byte {
+	if order == Descending {
+		for i := start; i < len(b); i++ {
+			b[i] = ^b[i]
+		}
+	}
+	return b
+}
+
+// AppendNull appends a nil value.
+func AppendNull(b []byte, order Order) []byte {
+	return invert(append(b, headerNull), len(b), order)
+}
+
+// AppendInt64 appends n.
+func AppendInt64(b []byte, n int64, order Order) []byte {
+	start := len(b)
+	b = append(b, headerInt64)
+	b = binary.BigEndian.AppendUint64(b, uint64(n)^1<<63)
+	return invert(b, start, order)
+}
+
+// AppendUint64 appends n.
+func AppendUint64(b []byte, n uint64, order Order) []byte {
+	start := len(b)
+	b = append(b, headerUint64)
+	b = binary.BigEndian.AppendUint64(b, n)
+	return invert(b, start, order)
+}
+
+// AppendFloat32 appends f. NaN sorts after +Inf, -0 before +0.
+func AppendFloat32(b []byte, f float32, order Order) []byte {
+	start := len(b)
+	bits := math.Float32bits(f)
+	bits ^= uint32(int32(bits)>>31) | 1<<31
+	b = append(b, headerFloat32)
+	b = binary.BigEndian.AppendUint32(b, bits)
+	return invert(b, start, order)
+}
+
+// AppendFloat64 appends f. NaN sorts after +Inf, -0 before +0.
+func AppendFloat64(b []byte, f float64, order Order) []byte {
+	start := len(b)
+	bits := math.Float64bits(f)
+	bits ^= uint64(int64(bits)>>63) | 1<<63
+	b = append(b, headerFloat64)
+	b = binary.BigEndian.AppendUint64(b, bits)
+	return invert(b, start, order)
+}
+
+// AppendString appends s. A string sorts before the strings it prefixes.
+func AppendString(b []byte, s string, order Order) []byte {
+	return appendEscaped(b, headerString, []byte(s), order)
+}
+
+// AppendBytes appends v, as AppendString.
+func AppendBytes(b []byte, v []byte, order Order) []byte {
+	return appendEscaped(b, headerBytes, v, order)
+}
+
+func appendEscaped(b []byte, header byte, v []byte, order Order) []byte {
+	start := len(b)
+	b = append(b, header)
+	for _, c := range v {
+		b = append(b, c)
+		if c == escape {
+			b = append(b, escaped)
+		}
+	}
+	b = append(b, escape, terminator)
+	return invert(b, start, order)
+}
+
+// Append appends the values, see Encode.
+func Append(b []byte, values ...interface{}) ([]byte, error) {
+	for i, v := range values {
+		order := Ascending
+		if d, ok := v.(Desc); ok {
+			v, order = d.V, Descending
+		}
+		switch v := v.(type) {
+		case nil:
+			b = AppendNull(b, order)
+		case int:
+			b = AppendInt64(b, int64(v), order)
+		case int8:
+			b = AppendInt64(b, int64(v), order)
+		case int16:
+			b = AppendInt64(b, int64(v), order)
+		case int32:
+			b = AppendInt64(b, int64(v), order)
+		case int64:
+			b = AppendInt64(b, v, order)
+		case uint:
+			b = AppendUint64(b, uint64(v), order)
+		case uint8:
+			b = AppendUint64(b, uint64(v), order)
+		case uint16:
+			b = AppendUint64(b, uint64(v), order)
+		case uint32:
+			b = AppendUint64(b, uint64(v), order)
+		case uint64:
+			b = AppendUint64(b, v, order)
+		case float32:
+			b = AppendFloat32(b, v, order)
+		case float64:
+			b = AppendFloat64(b, v, order)
+		case string:
+			b = AppendString(b, v, order)
+		case []byte:
+			b = AppendBytes(b, v, order)
+		default:
+			return nil, fmt.Errorf("rowkey: value %d has unsupported type %T", i, v)
+		}
+	}
+	return b, nil
+}
+
+// Encode encodes a tuple into a key. Values are nil, signed and unsigned
+// integers, decoded as int64 and uint64, float32, float64, string and
+// []byte, each optionally wrapped in a Desc.
+func Encode(values ...interface{}) ([]byte, error) {
+	return Append(nil, values...)
+}
+
+// Decode decodes every value of key, see Encode.
+func Decode(key []byte) ([]interface{}, error) {
+	var values []interface{}
+	for len(key) > 0 {
+		v, n, err := decodeOne(key)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		key = key[n:]
+	}
+	return values, nil
+}
+
+// DecodeFirst decodes the first value of key and return the remaining
+// bytes.
+func DecodeFirst(key []byte) (v interface{}, rest []byte, err error) {
+	if len(key) == 0 {
+		return nil, nil, ErrCorrupt
+	}
+	v, n, err := decodeOne(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, key[n:], nil
+}
+
+// decodeOne decodes the value key starts with and return its length.
+func decodeOne(key []byte) (v interface{}, n int, err error) {
+	header, order := key[0], Ascending
+	if header > 0x7f {
+		header, order = ^header, Descending
+	}
+	// fixed copies the n bytes following the header, in ascending order
+	fixed := func(size int) ([]byte, bool) {
+		if len(key) < 1+size {
+			return nil, false
+		}
+		b := append([]byte(nil), key[1:1+size]...)
+		return invert(b, 0, order), true
+	}
+	switch header {
+	case headerNull:
+		v, n = nil, 1
+	case headerInt64, headerUint64, headerFloat64:
+		b, ok := fixed(8)
+		if !ok {
+			return nil, 0, ErrCorrupt
+		}
+		bits := binary.BigEndian.Uint64(b)
+		switch header {
+		case headerInt64:
+			v = int64(bits ^ 1<<63)
+		case headerUint64:
+			v = bits
+		default:
+			if bits&(1<<63) != 0 {
+				bits ^= 1 << 63
+			} else {
+				bits = ^bits
+			}
+			v = math.Float64frombits(bits)
+		}
+		n = 9
+	case headerFloat32:
+		b, ok := fixed(4)
+		if !ok {
+			return nil, 0, ErrCorrupt
+		}
+		bits := binary.BigEndian.Uint32(b)
+		if bits&(1<<31) != 0 {
+			bits ^= 1 << 31
+		} else {
+			bits = ^bits
+		}
+		v, n = math.Float32frombits(bits), 5
+	case headerString, headerBytes:
+		var s []byte
+		if s, n, err = decodeEscaped(key, order); err != nil {
+			return nil, 0, err
+		}
+		if header == headerString {
+			v = string(s)
+		} else {
+			v = s
+		}
+	default:
+		return nil, 0, ErrCorrupt
+	}
+	if order == Descending {
+		v = Desc{V: v}
+	}
+	return v, n, nil
+}
+
+// decodeEscaped decodes the escaped bytes following the header of key.
+func decodeEscaped(key []byte, order Order) ([]byte, int, error) {
+	var mask byte
+	if order == Descending {
+		mask = 0xff
+	}
+	var s []byte
+	for i := 1; i < len(key); i++ {
+		c := key[i] ^ mask
+		if c != escape {
+			s = append(s, c)
+			continue
+		}
+		if i+1 == len(key) {
+			break
+		}
+		switch key[i+1] ^ mask {
+		case escaped:
+			s = append(s, escape)
+			i++
+		case terminator:
+			if s == nil {
+				s = []byte{}
+			}
+			return s, i + 2, nil
+		default:
+			return nil, 0, ErrCorrupt
+		}
+	}
+	return nil, 0, ErrCorrupt
+}
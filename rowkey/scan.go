@@ -0,0 +1,45 @@
+package rowkey
+
+import (
+	"github.com/J-J-J/hbase"
+)
+
+// PrefixEnd return the first key after every key starting with prefix, to
+// use as an exclusive stop row, nil when there is none.
+func PrefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// PrefixScan return a scan over the keys whose first values are values,
+// see Encode. Values are whole, a string prefix does not match longer
+// strings.
+func PrefixScan(values ...interface{}) (*hbase.TScan, error) {
+	prefix, err := Encode(values...)
+	if err != nil {
+		return nil, err
+	}
+	return &hbase.TScan{StartRow: prefix, StopRow: PrefixEnd(prefix)}, nil
+}
+
+// RangeScan return a scan over the keys from the one of start, included, to
+// the one of stop, excluded along with the keys it prefixes. Either may be
+// empty for an unbounded range. A start of (tenant, 10) and a stop of
+// (tenant, 20) selects the keys of tenant whose second value is in [10, 20).
+func RangeScan(start, stop []interface{}) (*hbase.TScan, error) {
+	startRow, err := Encode(start...)
+	if err != nil {
+		return nil, err
+	}
+	stopRow, err := Encode(stop...)
+	if err != nil {
+		return nil, err
+	}
+	return &hbase.TScan{StartRow: startRow, StopRow: stopRow}, nil
+}
@@ -0,0 +1,372 @@
+package hbase
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"errors"
+	"hash/fnv"
+
+	"github.com/J-J-J/hbase/Hbase"
+)
+
+// SaltedClient reads and writes a table whose row keys are prefixed with a
+// bucket byte, the hash of the key modulo the number of buckets, so that
+// monotonically increasing keys spread over as many regions. Callers use
+// the logical keys, the bucket byte is added on writes and stripped from the
+// rows read.
+//
+// Presplitting the table at the bucket bytes 1 to buckets-1 gives every
+// bucket its own region from the start. Filters and raw HClient calls see
+// the salted keys, see Key.
+type SaltedClient struct {
+	client  *HClient
+	buckets int
+}
+
+// NewSaltedClient return a SaltedClient over client with buckets buckets,
+// between 1 and 256. Every client of a table must use the same number.
+func NewSaltedClient(client *HClient, buckets int) (*SaltedClient, error) {
+	if buckets < 1 || buckets > 256 {
+		return nil, errors.New("hbase: salt buckets must be between 1 and 256")
+	}
+	return &SaltedClient{client: client, buckets: buckets}, nil
+}
+
+// Client return the underlying client.
+func (s *SaltedClient) Client() *HClient {
+	return s.client
+}
+
+// Bucket return the bucket of the logical key row.
+func (s *SaltedClient) Bucket(row []byte) byte {
+	h := fnv.New32a()
+	h.Write(row)
+	return byte(h.Sum32() % uint32(s.buckets))
+}
+
+// Key return the salted key stored for the logical key row.
+func (s *SaltedClient) Key(row []byte) []byte {
+	key := make([]byte, 0, len(row)+1)
+	return append(append(key, s.Bucket(row)), row...)
+}
+
+// unsalt strips the bucket byte of the rows in place.
+func unsalt(rows []*Hbase.TRowResult) []*Hbase.TRowResult {
+	for _, row := range rows {
+		if len(row.Row) > 0 {
+			row.Row = row.Row[1:]
+		}
+	}
+	return rows
+}
+
+// MutateRow is HClient.MutateRow on the logical key row.
+func (s *SaltedClient) MutateRow(tableName string, row []byte, mutations []*Hbase.Mutation, attributes map[string]string) error {
+	return s.MutateRowContext(context.Background(), tableName, row, mutations, attributes)
+}
+
+// MutateRowContext is MutateRow with a context.
+func (s *SaltedClient) MutateRowContext(ctx context.Context, tableName string, row []byte, mutations []*Hbase.Mutation, attributes map[string]string) error {
+	return s.client.MutateRowContext(ctx, tableName, s.Key(row), mutations, attributes)
+}
+
+// MutateRowTs is HClient.MutateRowTs on the logical key row.
+func (s *SaltedClient) MutateRowTs(tableName string, row []byte, mutations []*Hbase.Mutation, timestamp int64, attributes map[string]string) error {
+	return s.MutateRowTsContext(context.Background(), tableName, row, mutations, timestamp, attributes)
+}
+
+// MutateRowTsContext is MutateRowTs with a context.
+func (s *SaltedClient) MutateRowTsContext(ctx context.Context, tableName string, row []byte, mutations []*Hbase.Mutation, timestamp int64, attributes map[string]string) error {
+	return s.client.MutateRowTsContext(ctx, tableName, s.Key(row), mutations, timestamp, attributes)
+}
+
+// MutateRows is HClient.MutateRows on logical keys.
+func (s *SaltedClient) MutateRows(tableName string, rowBatches []*Hbase.BatchMutation, attributes map[string]string) error {
+	return s.MutateRowsContext(context.Background(), tableName, rowBatches, attributes)
+}
+
+// MutateRowsContext is MutateRows with a context.
+func (s *SaltedClient) MutateRowsContext(ctx context.Context, tableName string, rowBatches []*Hbase.BatchMutation, attributes map[string]string) error {
+	return s.client.MutateRowsContext(ctx, tableName, s.batches(rowBatches), attributes)
+}
+
+// MutateRowsTs is HClient.MutateRowsTs on logical keys.
+func (s *SaltedClient) MutateRowsTs(tableName string, rowBatches []*Hbase.BatchMutation, timestamp int64, attributes map[string]string) error {
+	return s.MutateRowsTsContext(context.Background(), tableName, rowBatches, timestamp, attributes)
+}
+
+// MutateRowsTsContext is MutateRowsTs with a context.
+func (s *SaltedClient) MutateRowsTsContext(ctx context.Context, tableName string, rowBatches []*Hbase.BatchMutation, timestamp int64, attributes map[string]string) error {
+	return s.client.MutateRowsTsContext(ctx, tableName, s.batches(rowBatches), timestamp, attributes)
+}
+
+// batches return rowBatches on salted keys.
+func (s *SaltedClient) batches(rowBatches []*Hbase.BatchMutation) []*Hbase.BatchMutation {
+	salted := make([]*Hbase.BatchMutation, len(rowBatches))
+	for i, batch := range rowBatches {
+		salted[i] = NewBatchMutation(s.Key(batch.Row), batch.Mutations)
+	}
+	return salted
+}
+
+// Get is HClient.Get on the logical key row.
+func (s *SaltedClient) Get(tableName string, row []byte, column string, attributes map[string]string) ([]*Hbase.TCell, error) {
+	return s.GetContext(context.Background(), tableName, row, column, attributes)
+}
+
+// GetContext is Get with a context.
+func (s *SaltedClient) GetContext(ctx context.Context, tableName string, row []byte, column string, attributes map[string]string) ([]*Hbase.TCell, error) {
+	return s.client.GetContext(ctx, tableName, s.Key(row), column, attributes)
+}
+
+// GetVer is HClient.GetVer on the logical key row.
+func (s *SaltedClient) GetVer(tableName string, row []byte, column string, numVersions int32, attributes map[string]string) ([]*Hbase.TCell, error) {
+	return s.GetVerContext(context.Background(), tableName, row, column, numVersions, attributes)
+}
+
+// GetVerContext is GetVer with a context.
+func (s *SaltedClient) GetVerContext(ctx context.Context, tableName string, row []byte, column string, numVersions int32, attributes map[string]string) ([]*Hbase.TCell, error) {
+	return s.client.GetVerContext(ctx, tableName, s.Key(row), column, numVersions, attributes)
+}
+
+// GetVerTs is HClient.GetVerTs on the logical key row.
+func (s *SaltedClient) GetVerTs(tableName string, row []byte, column string, timestamp int64, numVersions int32, attributes map[string]string) ([]*Hbase.TCell, error) {
+	return s.GetVerTsContext(context.Background(), tableName, row, column, timestamp, numVersions, attributes)
+}
+
+// GetVerTsContext is GetVerTs with a context.
+func (s *SaltedClient) GetVerTsContext(ctx context.Context, tableName string, row []byte, column string, timestamp int64, numVersions int32, attributes map[string]string) ([]*Hbase.TCell, error) {
+	return s.client.GetVerTsContext(ctx, tableName, s.Key(row), column, timestamp, numVersions, attributes)
+}
+
+// GetRow is HClient.GetRow on the logical key row.
+func (s *SaltedClient) GetRow(tableName string, row []byte, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	return s.GetRowContext(context.Background(), tableName, row, attributes)
+}
+
+// GetRowContext is GetRow with a context.
+func (s *SaltedClient) GetRowContext(ctx context.Context, tableName string, row []byte, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	rows, err := s.client.GetRowContext(ctx, tableName, s.Key(row), attributes)
+	return unsalt(rows), err
+}
+
+// GetRowTs is HClient.GetRowTs on the logical key row.
+func (s *SaltedClient) GetRowTs(tableName string, row []byte, timestamp int64, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	return s.GetRowTsContext(context.Background(), tableName, row, timestamp, attributes)
+}
+
+// GetRowTsContext is GetRowTs with a context.
+func (s *SaltedClient) GetRowTsContext(ctx context.Context, tableName string, row []byte, timestamp int64, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	rows, err := s.client.GetRowTsContext(ctx, tableName, s.Key(row), timestamp, attributes)
+	return unsalt(rows), err
+}
+
+// GetRowWithColumns is HClient.GetRowWithColumns on the logical key row.
+func (s *SaltedClient) GetRowWithColumns(tableName string, row []byte, columns []string, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	return s.GetRowWithColumnsContext(context.Background(), tableName, row, columns, attributes)
+}
+
+// GetRowWithColumnsContext is GetRowWithColumns with a context.
+func (s *SaltedClient) GetRowWithColumnsContext(ctx context.Context, tableName string, row []byte, columns []string, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	rows, err := s.client.GetRowWithColumnsContext(ctx, tableName, s.Key(row), columns, attributes)
+	return unsalt(rows), err
+}
+
+// GetRowWithColumnsTs is HClient.GetRowWithColumnsTs on the logical key row.
+func (s *SaltedClient) GetRowWithColumnsTs(tableName string, row []byte, columns []string, timestamp int64, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	return s.GetRowWithColumnsTsContext(context.Background(), tableName, row, columns, timestamp, attributes)
+}
+
+// GetRowWithColumnsTsContext is GetRowWithColumnsTs with a context.
+func (s *SaltedClient) GetRowWithColumnsTsContext(ctx context.Context, tableName string, row []byte, columns []string, timestamp int64, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	rows, err := s.client.GetRowWithColumnsTsContext(ctx, tableName, s.Key(row), columns, timestamp, attributes)
+	return unsalt(rows), err
+}
+
+// GetRows is HClient.GetRows on logical keys.
+func (s *SaltedClient) GetRows(tableName string, rows [][]byte, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	return s.GetRowsContext(context.Background(), tableName, rows, attributes)
+}
+
+// GetRowsContext is GetRows with a context.
+func (s *SaltedClient) GetRowsContext(ctx context.Context, tableName string, rows [][]byte, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	results, err := s.client.GetRowsContext(ctx, tableName, s.keys(rows), attributes)
+	return unsalt(results), err
+}
+
+// GetRowsTs is HClient.GetRowsTs on logical keys.
+func (s *SaltedClient) GetRowsTs(tableName string, rows [][]byte, timestamp int64, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	return s.GetRowsTsContext(context.Background(), tableName, rows, timestamp, attributes)
+}
+
+// GetRowsTsContext is GetRowsTs with a context.
+func (s *SaltedClient) GetRowsTsContext(ctx context.Context, tableName string, rows [][]byte, timestamp int64, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	results, err := s.client.GetRowsTsContext(ctx, tableName, s.keys(rows), timestamp, attributes)
+	return unsalt(results), err
+}
+
+// GetRowsWithColumns is HClient.GetRowsWithColumns on logical keys.
+func (s *SaltedClient) GetRowsWithColumns(tableName string, rows [][]byte, columns []string, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	return s.GetRowsWithColumnsContext(context.Background(), tableName, rows, columns, attributes)
+}
+
+// GetRowsWithColumnsContext is GetRowsWithColumns with a context.
+func (s *SaltedClient) GetRowsWithColumnsContext(ctx context.Context, tableName string, rows [][]byte, columns []string, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	results, err := s.client.GetRowsWithColumnsContext(ctx, tableName, s.keys(rows), columns, attributes)
+	return unsalt(results), err
+}
+
+// GetRowsWithColumnsTs is HClient.GetRowsWithColumnsTs on logical keys.
+func (s *SaltedClient) GetRowsWithColumnsTs(tableName string, rows [][]byte, columns []string, timestamp int64, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	return s.GetRowsWithColumnsTsContext(context.Background(), tableName, rows, columns, timestamp, attributes)
+}
+
+// GetRowsWithColumnsTsContext is GetRowsWithColumnsTs with a context.
+func (s *SaltedClient) GetRowsWithColumnsTsContext(ctx context.Context, tableName string, rows [][]byte, columns []string, timestamp int64, attributes map[string]string) ([]*Hbase.TRowResult, error) {
+	results, err := s.client.GetRowsWithColumnsTsContext(ctx, tableName, s.keys(rows), columns, timestamp, attributes)
+	return unsalt(results), err
+}
+
+func (s *SaltedClient) keys(rows [][]byte) [][]byte {
+	keys := make([][]byte, len(rows))
+	for i, row := range rows {
+		keys[i] = s.Key(row)
+	}
+	return keys
+}
+
+// Scan opens one scanner per bucket over the logical range of scan and
+// merges their rows in logical key order. Its columns, timestamp, caching
+// and filter apply to every bucket.
+func (s *SaltedClient) Scan(tableName string, scan *TScan, attributes map[string]string) (*SaltedScanner, error) {
+	return s.ScanContext(context.Background(), tableName, scan, attributes)
+}
+
+// ScanContext is Scan with a context, ctx also bounds every fetch.
+func (s *SaltedClient) ScanContext(ctx context.Context, tableName string, scan *TScan, attributes map[string]string) (*SaltedScanner, error) {
+	if scan == nil {
+		scan = &TScan{}
+	}
+	m := &SaltedScanner{}
+	for bucket := 0; bucket < s.buckets; bucket++ {
+		bucketScan := *scan
+		bucketScan.StartRow = append([]byte{byte(bucket)}, scan.StartRow...)
+		if len(scan.StopRow) > 0 {
+			bucketScan.StopRow = append([]byte{byte(bucket)}, scan.StopRow...)
+		} else if bucket < 255 {
+			bucketScan.StopRow = []byte{byte(bucket + 1)}
+		} else {
+			bucketScan.StopRow = nil
+		}
+		sc, err := s.client.ScanContext(ctx, tableName, &bucketScan, attributes)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		m.scanners = append(m.scanners, sc)
+	}
+	for _, sc := range m.scanners {
+		m.advance(sc)
+	}
+	return m, nil
+}
+
+// SaltedScanner merges the scanners of every bucket of a salted table, see
+// SaltedClient.Scan. It is used like a Scanner.
+type SaltedScanner struct {
+	scanners []*Scanner
+	heads    saltedHeap
+	row      *Hbase.TRowResult
+	err      error
+}
+
+// advance pushes the next row of sc onto the heap.
+func (m *SaltedScanner) advance(sc *Scanner) {
+	if sc.Next() {
+		heap.Push(&m.heads, saltedHead{row: sc.Row(), sc: sc})
+	} else if err := sc.Err(); err != nil && m.err == nil {
+		m.err = err
+	}
+}
+
+// Next advances to the next row in logical key order and reports whether
+// there is one. It return false at the end of the rows or on error, every
+// scanner is then closed.
+func (m *SaltedScanner) Next() bool {
+	if m.err != nil || len(m.heads) == 0 {
+		m.row = nil
+		m.Close()
+		return false
+	}
+	head := heap.Pop(&m.heads).(saltedHead)
+	m.advance(head.sc)
+	if m.err != nil {
+		m.row = nil
+		m.Close()
+		return false
+	}
+	m.row = head.row
+	m.row.Row = m.row.Row[1:]
+	return true
+}
+
+// Row return the current row, with its logical key.
+func (m *SaltedScanner) Row() *Hbase.TRowResult {
+	return m.row
+}
+
+// Err return the error that ended the iteration, if any.
+func (m *SaltedScanner) Err() error {
+	return m.err
+}
+
+// Close closes the scanner of every bucket and return the first error.
+func (m *SaltedScanner) Close() error {
+	var first error
+	for _, sc := range m.scanners {
+		if err := sc.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	m.heads = nil
+	return first
+}
+
+// All return an iterator over the remaining rows, see Scanner.All.
+func (m *SaltedScanner) All() func(yield func(*Hbase.TRowResult, error) bool) {
+	return func(yield func(*Hbase.TRowResult, error) bool) {
+		defer m.Close()
+		for m.Next() {
+			if !yield(m.row, nil) {
+				return
+			}
+		}
+		if m.err != nil {
+			yield(nil, m.err)
+		}
+	}
+}
+
+// saltedHead is the current row of a bucket scanner.
+type saltedHead struct {
+	row *Hbase.TRowResult
+	sc  *Scanner
+}
+
+// saltedHeap orders the bucket scanners by the logical key of their row.
+type saltedHeap []saltedHead
+
+func (h saltedHeap) Len() int { return len(h) }
+func (h saltedHeap) Less(i, j int) bool {
+	return bytes.Compare(h[i].row.Row[1:], h[j].row.Row[1:]) < 0
+}
+func (h saltedHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *saltedHeap) Push(x interface{}) { *h = append(*h, x.(saltedHead)) }
+func (h *saltedHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	old[len(old)-1] = saltedHead{}
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,180 @@
+package hbase
+
+import (
+	"context"
+
+	"github.com/J-J-J/hbase/Hbase"
+)
+
+// DefaultScanBatch is the number of rows a Scanner fetches per call when
+// TScan.Caching is not set.
+const DefaultScanBatch = 100
+
+type scanBatch struct {
+	rows []*Hbase.TRowResult
+	err  error
+}
+
+// Scanner iterates over the rows of a server side scanner. It fetches
+// batches of rows with ScannerGetList, the next batch being requested in
+// the background while the current one is consumed, and closes the server
+// side scanner once the rows are exhausted or a call fails. A Scanner is
+// not safe for concurrent use, but its client may be used meanwhile.
+//
+//	sc, err := client.Scan("table", &hbase.TScan{Caching: 500}, nil)
+//	if err != nil {
+//		return err
+//	}
+//	defer sc.Close()
+//	for sc.Next() {
+//		row := sc.Row()
+//		...
+//	}
+//	return sc.Err()
+type Scanner struct {
+	client *HClient
+	ctx    context.Context
+	id     int32
+	batch  int32
+
+	rows    []*Hbase.TRowResult
+	pos     int
+	row     *Hbase.TRowResult
+	pending chan scanBatch // batch being fetched, nil when none
+	err     error
+	closed  bool
+	resume  *scanResume // set for a resumable scanner
+}
+
+// Scan opens a scanner on tableName with scan, a nil scan reads the whole
+// table.
+func (client *HClient) Scan(tableName string, scan *TScan, attributes map[string]string) (*Scanner, error) {
+	return client.ScanContext(context.Background(), tableName, scan, attributes)
+}
+
+// ScanContext is Scan with a context, ctx also bounds every fetch of the
+// Scanner.
+func (client *HClient) ScanContext(ctx context.Context, tableName string, scan *TScan, attributes map[string]string) (*Scanner, error) {
+	if scan == nil {
+		scan = &TScan{}
+	}
+	id, err := client.ScannerOpenWithScanContext(ctx, tableName, scan, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return NewScanner(ctx, client, id, scan.Caching), nil
+}
+
+// NewScanner return a Scanner over the scanner id opened on client by any
+// of the ScannerOpen calls, fetching batch rows per call. batch <= 0 uses
+// DefaultScanBatch.
+func NewScanner(ctx context.Context, client *HClient, id int32, batch int32) *Scanner {
+	if batch <= 0 {
+		batch = DefaultScanBatch
+	}
+	s := &Scanner{client: client, ctx: ctx, id: id, batch: batch}
+	s.prefetch()
+	return s
+}
+
+// prefetch requests the next batch in the background.
+func (s *Scanner) prefetch() {
+	ch := make(chan scanBatch, 1)
+	s.pending = ch
+	go func() {
+		rows, err := s.client.ScannerGetListContext(s.ctx, s.id, s.batch)
+		ch <- scanBatch{rows: rows, err: err}
+	}()
+}
+
+// Next advances to the next row and reports whether there is one. It return
+// false at the end of the rows or on error, the scanner is then closed.
+func (s *Scanner) Next() bool {
+	if s.closed {
+		s.row = nil
+		return false
+	}
+	if s.pos < len(s.rows) {
+		s.row = s.rows[s.pos]
+		s.rows[s.pos] = nil
+		s.pos++
+		if s.resume != nil {
+			s.resume.advance(s.row.Row)
+		}
+		return true
+	}
+	b := <-s.pending
+	s.pending = nil
+	if b.err != nil && s.resume != nil {
+		if b.err = s.reopen(b.err); b.err == nil {
+			return s.Next()
+		}
+	}
+	if b.err != nil {
+		s.err = b.err
+		s.Close()
+		return false
+	}
+	if len(b.rows) == 0 {
+		if s.resume != nil {
+			s.resume.done = true
+		}
+		if err := s.Close(); err != nil && s.err == nil {
+			s.err = err
+		}
+		return false
+	}
+	s.rows, s.pos = b.rows, 0
+	s.prefetch()
+	return s.Next()
+}
+
+// Row return the current row, valid after Next returned true.
+func (s *Scanner) Row() *Hbase.TRowResult {
+	return s.row
+}
+
+// Err return the error that ended the iteration, if any.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// Close releases the server side scanner. It waits for a batch being
+// fetched and may be called more than once.
+func (s *Scanner) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.pending != nil {
+		<-s.pending
+		s.pending = nil
+	}
+	s.rows, s.row = nil, nil
+	// the lease is released even when ctx is already done
+	return s.client.ScannerCloseContext(context.Background(), s.id)
+}
+
+// All return an iterator over the remaining rows for use with range. An
+// error is yielded last with a nil row. Leaving the loop early closes the
+// scanner.
+//
+//	for row, err := range sc.All() {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (s *Scanner) All() func(yield func(*Hbase.TRowResult, error) bool) {
+	return func(yield func(*Hbase.TRowResult, error) bool) {
+		defer s.Close()
+		for s.Next() {
+			if !yield(s.row, nil) {
+				return
+			}
+		}
+		if s.err != nil {
+			yield(nil, s.err)
+		}
+	}
+}
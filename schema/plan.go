@@ -0,0 +1,308 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/J-J-J/hbase"
+)
+
+// StepKind is what a Step does to its table.
+type StepKind int
+
+const (
+	Create StepKind = iota
+	Enable
+	Disable
+	Delete
+)
+
+func (k StepKind) String() string {
+	switch k {
+	case Create:
+		return "create"
+	case Enable:
+		return "enable"
+	case Disable:
+		return "disable"
+	case Delete:
+		return "delete"
+	}
+	return "StepKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Step is one change of a Plan.
+type Step struct {
+	Kind  StepKind
+	Table string
+	// Families are the families a Create step creates the table with.
+	Families []*hbase.ColumnDescriptor
+	// Reason explains the step for the report.
+	Reason string
+	// Destructive is set on the steps losing data, deleting a table.
+	Destructive bool
+}
+
+func (s *Step) String() string {
+	text := s.Kind.String() + " " + s.Table
+	if s.Kind == Create {
+		names := make([]string, len(s.Families))
+		for i, f := range s.Families {
+			names[i] = familyName(f.Name)
+		}
+		text += " (" + strings.Join(names, ", ") + ")"
+	}
+	if s.Destructive {
+		text += " [destructive]"
+	}
+	if s.Reason != "" {
+		text += ": " + s.Reason
+	}
+	return text
+}
+
+// Plan is the ordered steps migrating a cluster to a Schema.
+type Plan struct {
+	Steps []*Step
+}
+
+// String return the dry-run report, one step per line.
+func (p *Plan) String() string {
+	if len(p.Steps) == 0 {
+		return "no changes\n"
+	}
+	var b strings.Builder
+	for i, s := range p.Steps {
+		b.WriteString(strconv.Itoa(i+1) + ". " + s.String() + "\n")
+	}
+	return b.String()
+}
+
+// Destructive reports whether a step of the plan is destructive.
+func (p *Plan) Destructive() bool {
+	for _, s := range p.Steps {
+		if s.Destructive {
+			return true
+		}
+	}
+	return false
+}
+
+// Plan compares the schema with the tables of client and return the steps
+// migrating them. It only reads from the cluster.
+func (s *Schema) Plan(client *hbase.HClient) (*Plan, error) {
+	return s.PlanContext(context.Background(), client)
+}
+
+// PlanContext is Plan with a context.
+func (s *Schema) PlanContext(ctx context.Context, client *hbase.HClient) (*Plan, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	names, err := client.GetTableNamesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(names))
+	for _, name := range names {
+		existing[name] = true
+	}
+	declared := make(map[string]bool, len(s.Tables))
+	p := &Plan{}
+	for _, t := range s.Tables {
+		declared[t.Name] = true
+		if !existing[t.Name] {
+			p.create(t, "missing")
+			continue
+		}
+		columns, err := client.GetColumnDescriptorsContext(ctx, t.Name)
+		if err != nil {
+			return nil, err
+		}
+		enabled, err := client.IsTableEnabledContext(ctx, t.Name)
+		if err != nil {
+			return nil, err
+		}
+		if diff := diffFamilies(t, columns); len(diff) > 0 {
+			p.drop(t.Name, enabled, "families changed: "+strings.Join(diff, "; "))
+			p.create(t, "families changed")
+			continue
+		}
+		switch {
+		case enabled && t.Disabled:
+			p.add(&Step{Kind: Disable, Table: t.Name, Reason: "declared disabled"})
+		case !enabled && !t.Disabled:
+			p.add(&Step{Kind: Enable, Table: t.Name, Reason: "declared enabled"})
+		}
+	}
+	if s.Prune {
+		sort.Strings(names)
+		for _, name := range names {
+			if declared[name] {
+				continue
+			}
+			enabled, err := client.IsTableEnabledContext(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			p.drop(name, enabled, "not declared")
+		}
+	}
+	return p, nil
+}
+
+func (p *Plan) add(s *Step) {
+	p.Steps = append(p.Steps, s)
+}
+
+// create adds the steps creating t, disabling it when declared so.
+func (p *Plan) create(t *Table, reason string) {
+	families := make([]*hbase.ColumnDescriptor, len(t.Families))
+	for i, f := range t.Families {
+		c := *f
+		c.Name = familyName(f.Name) + ":"
+		families[i] = &c
+	}
+	p.add(&Step{Kind: Create, Table: t.Name, Families: families, Reason: reason})
+	if t.Disabled {
+		p.add(&Step{Kind: Disable, Table: t.Name, Reason: "declared disabled"})
+	}
+}
+
+// drop adds the steps deleting a table, which must be disabled first.
+func (p *Plan) drop(table string, enabled bool, reason string) {
+	if enabled {
+		p.add(&Step{Kind: Disable, Table: table, Reason: "before delete"})
+	}
+	p.add(&Step{Kind: Delete, Table: table, Reason: reason, Destructive: true})
+}
+
+// diffFamilies describes the differences between the families of t and
+// those of the cluster, nil when they match.
+func diffFamilies(t *Table, have map[string]*hbase.ColumnDescriptor) []string {
+	current := make(map[string]*hbase.ColumnDescriptor, len(have))
+	for name, f := range have {
+		current[familyName(name)] = f
+	}
+	var diff []string
+	seen := make(map[string]bool, len(t.Families))
+	for _, w := range t.Families {
+		name := familyName(w.Name)
+		seen[name] = true
+		h, ok := current[name]
+		if !ok {
+			diff = append(diff, "family "+name+" added")
+			continue
+		}
+		if changes := diffFamily(w, h, t.given[name]); len(changes) > 0 {
+			diff = append(diff, "family "+name+" "+strings.Join(changes, ", "))
+		}
+	}
+	var removed []string
+	for name := range current {
+		if !seen[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		diff = append(diff, "family "+name+" removed")
+	}
+	return diff
+}
+
+// diffFamily lists the settings of have differing from want, only those in
+// given, by lower case name, unless given is nil.
+func diffFamily(want, have *hbase.ColumnDescriptor, given map[string]bool) []string {
+	var changes []string
+	change := func(field, from, to string) {
+		changes = append(changes, field+" "+from+" -> "+to)
+	}
+	compare := func(field string) bool {
+		return given == nil || given[strings.ToLower(field)]
+	}
+	if compare("maxVersions") && want.MaxVersions != have.MaxVersions {
+		change("maxVersions", itoa(have.MaxVersions), itoa(want.MaxVersions))
+	}
+	if compare("compression") && !strings.EqualFold(want.Compression, have.Compression) {
+		change("compression", have.Compression, want.Compression)
+	}
+	if compare("bloomFilterType") && !strings.EqualFold(want.BloomFilterType, have.BloomFilterType) {
+		change("bloomFilterType", have.BloomFilterType, want.BloomFilterType)
+	}
+	if compare("inMemory") && want.InMemory != have.InMemory {
+		change("inMemory", strconv.FormatBool(have.InMemory), strconv.FormatBool(want.InMemory))
+	}
+	if compare("blockCacheEnabled") && want.BlockCacheEnabled != have.BlockCacheEnabled {
+		change("blockCacheEnabled", strconv.FormatBool(have.BlockCacheEnabled), strconv.FormatBool(want.BlockCacheEnabled))
+	}
+	if compare("timeToLive") && want.TimeToLive != have.TimeToLive {
+		change("timeToLive", itoa(have.TimeToLive), itoa(want.TimeToLive))
+	}
+	return changes
+}
+
+func itoa(n int32) string {
+	return strconv.Itoa(int(n))
+}
+
+// ErrDestructive is returned by Apply for a destructive plan it is not
+// allowed to run.
+var ErrDestructive = errors.New("schema: plan is destructive")
+
+// ApplyOptions are the options of Apply.
+type ApplyOptions struct {
+	// AllowDestructive lets Apply delete tables.
+	AllowDestructive bool
+}
+
+// StepError is the error of the step Apply stopped at.
+type StepError struct {
+	Step *Step
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return "schema: " + e.Step.Kind.String() + " " + e.Step.Table + ": " + e.Err.Error()
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Apply runs the steps of the plan in order, stopping at the first error.
+// A destructive plan is refused with ErrDestructive before any step runs
+// unless opts allows it. Plan again after an error: the steps that ran are
+// not undone.
+func (p *Plan) Apply(client *hbase.HClient, opts ApplyOptions) error {
+	return p.ApplyContext(context.Background(), client, opts)
+}
+
+// ApplyContext is Apply with a context.
+func (p *Plan) ApplyContext(ctx context.Context, client *hbase.HClient, opts ApplyOptions) error {
+	if p.Destructive() && !opts.AllowDestructive {
+		return ErrDestructive
+	}
+	for _, s := range p.Steps {
+		var err error
+		switch s.Kind {
+		case Create:
+			_, err = client.CreateTableContext(ctx, s.Table, s.Families)
+		case Enable:
+			err = client.EnableTableContext(ctx, s.Table)
+		case Disable:
+			err = client.DisableTableContext(ctx, s.Table)
+		case Delete:
+			err = client.DeleteTableContext(ctx, s.Table)
+		default:
+			err = errors.New("unknown step kind " + s.Kind.String())
+		}
+		if err != nil {
+			return &StepError{Step: s, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+// Package schema declares tables and their column families, and migrates a
+// cluster to them. A Schema is written in Go or loaded from JSON or YAML:
+//
+//	tables:
+//	  - name: events
+//	    families:
+//	      - name: d
+//	        maxVersions: 3
+//	        compression: SNAPPY
+//	      - name: meta
+//
+// Plan compares it with the cluster and lists the steps migrating the
+// cluster, its String method being the dry-run report, and Apply runs them:
+//
+//	plan, err := s.Plan(client)
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Print(plan)
+//	err = plan.Apply(client, schema.ApplyOptions{})
+//
+// The thrift gateway cannot alter a table, so a table whose families
+// changed is dropped and created again, losing its data. Steps dropping
+// data are destructive and Apply refuses them unless allowed.
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/J-J-J/hbase"
+)
+
+// Schema is the desired set of tables.
+type Schema struct {
+	Tables []*Table `json:"tables"`
+	// Prune drops the tables of the cluster the schema does not declare.
+	Prune bool `json:"prune,omitempty"`
+}
+
+// Table is a desired table.
+type Table struct {
+	Name     string                    `json:"name"`
+	Families []*hbase.ColumnDescriptor `json:"families"`
+	// Disabled keeps the table disabled.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// given holds, per family name, the lower case names of the settings a
+	// schema file sets. Plan leaves the others to the cluster. It is nil
+	// for the tables built in Go, whose settings are all compared.
+	given map[string]map[string]bool
+}
+
+// DefaultFamily return a family with the defaults the thrift gateway applies
+// to the fields of a ColumnDescriptor it is not given, the defaults of the
+// families loaded from JSON or YAML when their table is created.
+func DefaultFamily(name string) *hbase.ColumnDescriptor {
+	return &hbase.ColumnDescriptor{
+		Name:              name,
+		MaxVersions:       3,
+		Compression:       "NONE",
+		BloomFilterType:   "NONE",
+		BlockCacheEnabled: false,
+		TimeToLive:        0x7fffffff,
+	}
+}
+
+// familyName return name without the colon the gateway appends.
+func familyName(name string) string {
+	return strings.TrimSuffix(name, ":")
+}
+
+// Validate checks that the tables have a name, at least one family, and no
+// duplicate table or family.
+func (s *Schema) Validate() error {
+	tables := make(map[string]bool, len(s.Tables))
+	for _, t := range s.Tables {
+		if t.Name == "" {
+			return errors.New("schema: table without a name")
+		}
+		if tables[t.Name] {
+			return errors.New("schema: table " + t.Name + " is declared twice")
+		}
+		tables[t.Name] = true
+		if len(t.Families) == 0 {
+			return errors.New("schema: table " + t.Name + " has no family")
+		}
+		families := make(map[string]bool, len(t.Families))
+		for _, f := range t.Families {
+			name := familyName(f.Name)
+			if name == "" || strings.Contains(name, ":") {
+				return errors.New("schema: table " + t.Name + " has an invalid family name " + f.Name)
+			}
+			if families[name] {
+				return errors.New("schema: table " + t.Name + " declares family " + name + " twice")
+			}
+			families[name] = true
+			if f.MaxVersions <= 0 {
+				return errors.New("schema: family " + t.Name + ":" + name + " needs a positive maxVersions")
+			}
+		}
+	}
+	return nil
+}
+
+// jsonSchema is the JSON form of a Schema, families being decoded over
+// DefaultFamily.
+type jsonSchema struct {
+	Tables []struct {
+		Name     string            `json:"name"`
+		Families []json.RawMessage `json:"families"`
+		Disabled bool              `json:"disabled"`
+	} `json:"tables"`
+	Prune bool `json:"prune"`
+}
+
+// ParseJSON parses and validates a schema in JSON. The family fields are
+// named as the fields of hbase.ColumnDescriptor, in any case. The missing
+// ones take the values of DefaultFamily when the table is created, and are
+// not compared with the families of the cluster by Plan.
+func ParseJSON(data []byte) (*Schema, error) {
+	var js jsonSchema
+	if err := json.Unmarshal(data, &js); err != nil {
+		return nil, errors.New("schema: " + err.Error())
+	}
+	s := &Schema{Prune: js.Prune}
+	for _, jt := range js.Tables {
+		t := &Table{Name: jt.Name, Disabled: jt.Disabled}
+		for _, raw := range jt.Families {
+			f := DefaultFamily("")
+			if err := json.Unmarshal(raw, f); err != nil {
+				return nil, errors.New("schema: table " + jt.Name + ": " + err.Error())
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &fields); err != nil {
+				return nil, errors.New("schema: table " + jt.Name + ": " + err.Error())
+			}
+			given := make(map[string]bool, len(fields))
+			for field := range fields {
+				given[strings.ToLower(field)] = true
+			}
+			if t.given == nil {
+				t.given = make(map[string]map[string]bool)
+			}
+			t.given[familyName(f.Name)] = given
+			t.Families = append(t.Families, f)
+		}
+		s.Tables = append(s.Tables, t)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ParseYAML parses and validates a schema in YAML, as ParseJSON. Only the
+// block style subset of YAML is supported: mappings, sequences, plain and
+// quoted scalars and comments, which covers schema files.
+func ParseYAML(data []byte) (*Schema, error) {
+	v, err := parseYAML(string(data))
+	if err != nil {
+		return nil, err
+	}
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.New("schema: " + err.Error())
+	}
+	return ParseJSON(js)
+}
+
+// Load reads a schema file, in YAML when its extension is .yaml or .yml and
+// in JSON otherwise.
+func Load(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseYAML(data)
+	}
+	return ParseJSON(data)
+}
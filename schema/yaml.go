@@ -0,0 +1,278 @@
+package schema
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// YAMLError is an invalid or unsupported YAML document.
+type YAMLError struct {
+	Line int
+	Msg  string
+}
+
+func (e *YAMLError) Error() string {
+	return "schema: yaml line " + strconv.Itoa(e.Line) + ": " + e.Msg
+}
+
+// yamlLine is a significant line, indent being the column of its text.
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// yamlParser parses block style YAML into the values encoding/json
+// produces: map[string]interface{}, []interface{}, string, float64, bool
+// and nil.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func parseYAML(src string) (interface{}, error) {
+	p := &yamlParser{}
+	for i, text := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(text, "---") || strings.HasPrefix(text, "%") {
+			continue
+		}
+		stripped := strings.TrimRight(stripComment(text), " \t")
+		trimmed := strings.TrimLeft(stripped, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if strings.Contains(stripped[:len(stripped)-len(trimmed)], "\t") {
+			return nil, &YAMLError{Line: i + 1, Msg: "tabs are not allowed for indentation"}
+		}
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: len(stripped) - len(trimmed), text: trimmed})
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	v, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, &YAMLError{Line: p.lines[p.pos].num, Msg: "unexpected indentation"}
+	}
+	return v, nil
+}
+
+// stripComment removes a comment, a # at the start or after a space outside
+// quotes.
+func stripComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+func (p *yamlParser) errorf(line yamlLine, msg string) error {
+	return &YAMLError{Line: line.num, Msg: msg}
+}
+
+// parseBlock parses the sequence or mapping whose lines start at indent.
+func (p *yamlParser) parseBlock(indent int) (interface{}, error) {
+	line := p.lines[p.pos]
+	if line.text == "-" || strings.HasPrefix(line.text, "- ") {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitKey(line.text); ok {
+		return p.parseMapping(indent)
+	}
+	p.pos++
+	return parseScalar(line.text, line)
+}
+
+// parseSequence parses the items starting with "- " at indent.
+func (p *yamlParser) parseSequence(indent int) (interface{}, error) {
+	items := []interface{}{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf(line, "unexpected indentation")
+		}
+		if line.text != "-" && !strings.HasPrefix(line.text, "- ") {
+			break
+		}
+		if line.text == "-" {
+			p.pos++
+			item, err := p.parseNested(indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+		// the item continues on this line, as if its text started a line
+		rest := strings.TrimLeft(line.text[1:], " ")
+		p.lines[p.pos] = yamlLine{num: line.num, indent: line.indent + len(line.text) - len(rest), text: rest}
+		item, err := p.parseBlock(p.lines[p.pos].indent)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseMapping parses the "key: value" lines at indent.
+func (p *yamlParser) parseMapping(indent int) (interface{}, error) {
+	m := map[string]interface{}{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf(line, "unexpected indentation")
+		}
+		key, value, ok := splitKey(line.text)
+		if !ok {
+			return nil, p.errorf(line, "expected key: value")
+		}
+		if _, dup := m[key]; dup {
+			return nil, p.errorf(line, "duplicate key "+key)
+		}
+		p.pos++
+		if value != "" {
+			v, err := parseScalar(value, line)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+			continue
+		}
+		v, err := p.parseNested(indent)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// parseNested parses the block following a key or a dash at indent: lines
+// indented deeper, or a sequence at the same indentation, nil when none.
+func (p *yamlParser) parseNested(indent int) (interface{}, error) {
+	if p.pos == len(p.lines) {
+		return nil, nil
+	}
+	next := p.lines[p.pos]
+	switch {
+	case next.indent > indent:
+		return p.parseBlock(next.indent)
+	case next.indent == indent && (next.text == "-" || strings.HasPrefix(next.text, "- ")):
+		return p.parseSequence(indent)
+	}
+	return nil, nil
+}
+
+// splitKey splits "key: value" or "key:", the key being plain or quoted.
+func splitKey(text string) (key, value string, ok bool) {
+	if text[0] == '\'' || text[0] == '"' {
+		end := closingQuote(text)
+		if end < 0 || end+1 >= len(text) || text[end+1] != ':' {
+			return "", "", false
+		}
+		k, err := unquote(text[:end+1])
+		if err != nil {
+			return "", "", false
+		}
+		rest := text[end+2:]
+		if rest != "" && rest[0] != ' ' {
+			return "", "", false
+		}
+		return k, strings.TrimSpace(rest), true
+	}
+	i := strings.Index(text, ": ")
+	if i < 0 {
+		if !strings.HasSuffix(text, ":") {
+			return "", "", false
+		}
+		i = len(text) - 1
+	}
+	return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+}
+
+// closingQuote return the index of the quote closing the string text starts
+// with, -1 when there is none.
+func closingQuote(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case text[i] == quote:
+			if quote == '\'' && i+1 < len(text) && text[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+func unquote(s string) (string, error) {
+	if s[0] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	return strconv.Unquote(s)
+}
+
+// parseScalar parses a scalar, or the empty flow collections [] and {}.
+func parseScalar(text string, line yamlLine) (interface{}, error) {
+	switch text {
+	case "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	case "[]":
+		return []interface{}{}, nil
+	case "{}":
+		return map[string]interface{}{}, nil
+	}
+	switch text[0] {
+	case '\'', '"':
+		if closingQuote(text) != len(text)-1 {
+			return nil, &YAMLError{Line: line.num, Msg: "invalid quoted string"}
+		}
+		s, err := unquote(text)
+		if err != nil {
+			return nil, &YAMLError{Line: line.num, Msg: "invalid quoted string"}
+		}
+		return s, nil
+	case '[', '{', '&', '*', '!', '|', '>':
+		return nil, &YAMLError{Line: line.num, Msg: "unsupported YAML syntax " + strconv.Quote(text[:1])}
+	}
+	if strings.HasPrefix(text, "0x") {
+		if n, err := strconv.ParseInt(text[2:], 16, 64); err == nil {
+			return float64(n), nil
+		}
+	}
+	if strings.ContainsAny(text[:1], "+-.0123456789") {
+		if f, err := strconv.ParseFloat(text, 64); err == nil && !math.IsInf(f, 0) {
+			return f, nil
+		}
+	}
+	return text, nil
+}
@@ -0,0 +1,104 @@
+package thrift
+
+import (
+	"bufio"
+	"time"
+)
+
+// DEFAULT_BUFFER_SIZE is the read and write buffer size of a
+// TBufferedTransport.
+const DEFAULT_BUFFER_SIZE = 4096
+
+// TBufferedTransport buffers reads and writes of another transport, so the
+// many small reads of a protocol do not each hit the network.
+type TBufferedTransport struct {
+	transport TTransport
+	reader    *bufio.Reader
+	writer    *bufio.Writer
+}
+
+/**
+ * Wraps t with read and write buffers of bufferSize bytes.
+ *
+ * @param t Underlying transport
+ * @param bufferSize Buffer size, <= 0 uses DEFAULT_BUFFER_SIZE
+ */
+func NewTBufferedTransport(t TTransport, bufferSize int) *TBufferedTransport {
+	if bufferSize <= 0 {
+		bufferSize = DEFAULT_BUFFER_SIZE
+	}
+	return &TBufferedTransport{
+		transport: t,
+		reader:    bufio.NewReaderSize(t, bufferSize),
+		writer:    bufio.NewWriterSize(t, bufferSize),
+	}
+}
+
+type tBufferedTransportFactory struct {
+	bufferSize int
+}
+
+// NewTBufferedTransportFactory return a factory buffering every transport,
+// bufferSize <= 0 uses DEFAULT_BUFFER_SIZE.
+func NewTBufferedTransportFactory(bufferSize int) TTransportFactory {
+	return &tBufferedTransportFactory{bufferSize: bufferSize}
+}
+
+func (p *tBufferedTransportFactory) GetTransport(trans TTransport) TTransport {
+	return NewTBufferedTransport(trans, p.bufferSize)
+}
+
+func (p *TBufferedTransport) IsOpen() bool {
+	return p.transport.IsOpen()
+}
+
+// Open opens the underlying transport and drops anything still buffered.
+func (p *TBufferedTransport) Open() error {
+	p.reader.Reset(p.transport)
+	p.writer.Reset(p.transport)
+	return p.transport.Open()
+}
+
+// Close closes the underlying transport and drops anything still buffered.
+func (p *TBufferedTransport) Close() error {
+	p.reader.Reset(p.transport)
+	p.writer.Reset(p.transport)
+	return p.transport.Close()
+}
+
+func (p *TBufferedTransport) Read(buf []byte) (int, error) {
+	n, err := p.reader.Read(buf)
+	return n, NewTTransportExceptionFromOsError(err)
+}
+
+// ReadAll buf
+func (p *TBufferedTransport) ReadAll(buf []byte) (int, error) {
+	return ReadAllTransport(p, buf)
+}
+
+func (p *TBufferedTransport) Write(buf []byte) (int, error) {
+	n, err := p.writer.Write(buf)
+	return n, NewTTransportExceptionFromOsError(err)
+}
+
+// Flush writes out the write buffer and flushes the underlying transport.
+func (p *TBufferedTransport) Flush() error {
+	if err := p.writer.Flush(); err != nil {
+		p.writer.Reset(p.transport)
+		return NewTTransportExceptionFromOsError(err)
+	}
+	return NewTTransportExceptionFromOsError(p.transport.Flush())
+}
+
+func (p *TBufferedTransport) Peek() bool {
+	return p.reader.Buffered() > 0 || p.transport.Peek()
+}
+
+// SetDeadline forwards to the underlying transport when it supports
+// deadlines.
+func (p *TBufferedTransport) SetDeadline(t time.Time) error {
+	if dt, ok := p.transport.(TDeadlineTransport); ok {
+		return dt.SetDeadline(t)
+	}
+	return nil
+}
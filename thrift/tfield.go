@@ -2,6 +2,7 @@ package thrift
 
 import (
 	"sort"
+	"strconv"
 )
 
 // TField Helper class that encapsulates field metadata.
@@ -45,7 +46,7 @@ func (p *tField) ID() int {
 }
 
 func (p *tField) String() string {
-	return "<TField name:'" + p.name + "' type:" + string(p.typeID) + " field-id:" + string(p.id) + ">"
+	return "<TField name:'" + p.name + "' type:" + strconv.Itoa(int(p.typeID)) + " field-id:" + strconv.Itoa(int(p.id)) + ">"
 }
 
 type tFieldArray []TField
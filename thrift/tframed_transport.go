@@ -0,0 +1,158 @@
+package thrift
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"time"
+)
+
+// DEFAULT_MAX_FRAME_SIZE is the frame size limit used by the java server.
+const DEFAULT_MAX_FRAME_SIZE = 16384000
+
+// TFramedTransport prefixes every message with its 4 byte big endian length,
+// as required by nonblocking, half-sync/half-async and threaded selector
+// servers.
+type TFramedTransport struct {
+	transport   TTransport
+	maxLength   int
+	writeBuffer *bytes.Buffer
+	frameSize   int // bytes left in the frame being read
+	header      []byte
+}
+
+// NewTFramedTransport wraps t with the default frame size limit.
+func NewTFramedTransport(t TTransport) *TFramedTransport {
+	return NewTFramedTransportMaxLength(t, DEFAULT_MAX_FRAME_SIZE)
+}
+
+/**
+ * Wraps t, rejecting frames larger than maxLength in either direction.
+ *
+ * @param t Underlying transport
+ * @param maxLength Maximum frame size, <= 0 uses DEFAULT_MAX_FRAME_SIZE
+ */
+func NewTFramedTransportMaxLength(t TTransport, maxLength int) *TFramedTransport {
+	if maxLength <= 0 {
+		maxLength = DEFAULT_MAX_FRAME_SIZE
+	}
+	return &TFramedTransport{
+		transport:   t,
+		maxLength:   maxLength,
+		writeBuffer: bytes.NewBuffer(make([]byte, 0, 1024)),
+		header:      make([]byte, 4),
+	}
+}
+
+type tFramedTransportFactory struct {
+	maxLength int
+}
+
+// NewTFramedTransportFactory return a factory framing every transport,
+// maxLength <= 0 uses DEFAULT_MAX_FRAME_SIZE.
+func NewTFramedTransportFactory(maxLength int) TTransportFactory {
+	return &tFramedTransportFactory{maxLength: maxLength}
+}
+
+func (p *tFramedTransportFactory) GetTransport(trans TTransport) TTransport {
+	return NewTFramedTransportMaxLength(trans, p.maxLength)
+}
+
+func (p *TFramedTransport) IsOpen() bool {
+	return p.transport.IsOpen()
+}
+
+// Open opens the underlying transport and forgets any partial frame.
+func (p *TFramedTransport) Open() error {
+	p.reset()
+	return p.transport.Open()
+}
+
+// Close closes the underlying transport and forgets any partial frame.
+func (p *TFramedTransport) Close() error {
+	p.reset()
+	return p.transport.Close()
+}
+
+func (p *TFramedTransport) reset() {
+	p.frameSize = 0
+	p.writeBuffer.Reset()
+}
+
+// Read reads from the current frame, reading the next frame header first
+// when the current one is exhausted.
+func (p *TFramedTransport) Read(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	if p.frameSize == 0 {
+		if err := p.readFrameHeader(); err != nil {
+			return 0, err
+		}
+	}
+	if len(buf) > p.frameSize {
+		buf = buf[:p.frameSize]
+	}
+	n, err := p.transport.Read(buf)
+	p.frameSize -= n
+	if n > 0 {
+		return n, nil
+	}
+	return n, NewTTransportExceptionFromOsError(err)
+}
+
+func (p *TFramedTransport) readFrameHeader() error {
+	if _, err := p.transport.ReadAll(p.header); err != nil {
+		return NewTTransportExceptionFromOsError(err)
+	}
+	size := int(int32(binary.BigEndian.Uint32(p.header)))
+	if size < 0 || size > p.maxLength {
+		return NewTTransportException(UNKNOWN_TRANSPORT_EXCEPTION, "Incorrect frame size ("+strconv.Itoa(size)+")")
+	}
+	p.frameSize = size
+	return nil
+}
+
+// ReadAll buf
+func (p *TFramedTransport) ReadAll(buf []byte) (int, error) {
+	return ReadAllTransport(p, buf)
+}
+
+// Write buffers buf until the next Flush.
+func (p *TFramedTransport) Write(buf []byte) (int, error) {
+	if p.writeBuffer.Len()+len(buf) > p.maxLength {
+		return 0, NewTTransportException(UNKNOWN_TRANSPORT_EXCEPTION, "Frame size exceeds "+strconv.Itoa(p.maxLength))
+	}
+	return p.writeBuffer.Write(buf)
+}
+
+// Flush writes the buffered message as one frame.
+func (p *TFramedTransport) Flush() error {
+	size := p.writeBuffer.Len()
+	binary.BigEndian.PutUint32(p.header, uint32(size))
+	if _, err := p.transport.Write(p.header); err != nil {
+		p.writeBuffer.Reset()
+		return NewTTransportExceptionFromOsError(err)
+	}
+	if size > 0 {
+		_, err := p.writeBuffer.WriteTo(p.transport)
+		if err != nil {
+			p.writeBuffer.Reset()
+			return NewTTransportExceptionFromOsError(err)
+		}
+	}
+	return NewTTransportExceptionFromOsError(p.transport.Flush())
+}
+
+func (p *TFramedTransport) Peek() bool {
+	return p.frameSize > 0 || p.transport.Peek()
+}
+
+// SetDeadline forwards to the underlying transport when it supports
+// deadlines.
+func (p *TFramedTransport) SetDeadline(t time.Time) error {
+	if dt, ok := p.transport.(TDeadlineTransport); ok {
+		return dt.SetDeadline(t)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package thrift
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// THttpClient is a TTransport that buffers a request and POSTs it to a
+// thrift servlet on Flush, the response body is then read back as the
+// reply. Connections are reused through the underlying http.Client.
+type THttpClient struct {
+	client        *http.Client
+	url           *url.URL
+	header        http.Header
+	cookies       []*http.Cookie
+	requestBuffer *bytes.Buffer
+	response      *http.Response
+	flushErr      error // failure of the last request, reported by Read
+
+	mu       sync.Mutex // guards deadline and cancel against SetDeadline
+	deadline time.Time
+	cancel   context.CancelFunc
+}
+
+// NewTHttpClient return a transport posting to urlstr with
+// http.DefaultClient.
+func NewTHttpClient(urlstr string) (*THttpClient, error) {
+	return NewTHttpClientWithClient(urlstr, nil)
+}
+
+/**
+ * Creates a transport posting to urlstr.
+ *
+ * @param urlstr Endpoint of the thrift servlet
+ * @param client Client used for the requests, nil uses http.DefaultClient
+ */
+func NewTHttpClientWithClient(urlstr string, client *http.Client) (*THttpClient, error) {
+	parsedURL, err := url.Parse(urlstr)
+	if err != nil {
+		return nil, NewTTransportException(NOT_OPEN, err.Error())
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-thrift")
+	header.Set("Accept", "application/x-thrift")
+	return &THttpClient{
+		client:        client,
+		url:           parsedURL,
+		header:        header,
+		requestBuffer: bytes.NewBuffer(make([]byte, 0, 1024)),
+	}, nil
+}
+
+// SetHeader sets a header sent with every request.
+func (p *THttpClient) SetHeader(key, value string) {
+	p.header.Set(key, value)
+}
+
+// GetHeader return a header sent with every request.
+func (p *THttpClient) GetHeader(key string) string {
+	return p.header.Get(key)
+}
+
+// DelHeader removes a header sent with every request.
+func (p *THttpClient) DelHeader(key string) {
+	p.header.Del(key)
+}
+
+// AddCookie adds a cookie sent with every request. Cookies set by the
+// server are only kept when the http.Client has a Jar.
+func (p *THttpClient) AddCookie(cookie *http.Cookie) {
+	p.cookies = append(p.cookies, cookie)
+}
+
+// SetBasicAuth sends HTTP basic authentication with every request.
+func (p *THttpClient) SetBasicAuth(username, password string) {
+	req := http.Request{Header: http.Header{}}
+	req.SetBasicAuth(username, password)
+	p.header.Set("Authorization", req.Header.Get("Authorization"))
+}
+
+// IsOpen is always true, requests are sent on Flush.
+func (p *THttpClient) IsOpen() bool {
+	return true
+}
+
+// Open is a no-op, requests are sent on Flush.
+func (p *THttpClient) Open() error {
+	return nil
+}
+
+// Close drops the pending request and response.
+func (p *THttpClient) Close() error {
+	p.requestBuffer.Reset()
+	return p.closeResponse()
+}
+
+// closeResponse drains the previous response body so its connection can be
+// reused.
+func (p *THttpClient) closeResponse() error {
+	var err error
+	if p.response != nil {
+		io.Copy(io.Discard, p.response.Body)
+		err = p.response.Body.Close()
+		p.response = nil
+	}
+	p.mu.Lock()
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+	p.mu.Unlock()
+	return err
+}
+
+func (p *THttpClient) Read(buf []byte) (int, error) {
+	if p.response == nil {
+		if p.flushErr != nil {
+			return 0, p.flushErr
+		}
+		return 0, NewTTransportException(NOT_OPEN, "Response buffer is empty, no request.")
+	}
+	n, err := p.response.Body.Read(buf)
+	if n > 0 && err == io.EOF {
+		err = nil
+	}
+	return n, NewTTransportExceptionFromOsError(err)
+}
+
+// ReadAll buf
+func (p *THttpClient) ReadAll(buf []byte) (int, error) {
+	if p.response == nil && p.flushErr != nil {
+		return 0, p.flushErr
+	}
+	return ReadAllTransport(p, buf)
+}
+
+// Write buffers buf until the next Flush.
+func (p *THttpClient) Write(buf []byte) (int, error) {
+	return p.requestBuffer.Write(buf)
+}
+
+// Flush POSTs the buffered request and opens its response for reading.
+func (p *THttpClient) Flush() error {
+	p.flushErr = p.post()
+	return p.flushErr
+}
+
+func (p *THttpClient) post() error {
+	p.closeResponse()
+
+	body := bytes.NewReader(p.requestBuffer.Bytes())
+	p.requestBuffer = bytes.NewBuffer(make([]byte, 0, p.requestBuffer.Cap()))
+
+	ctx, err := p.requestContext()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url.String(), body)
+	if err != nil {
+		return NewTTransportExceptionFromOsError(err)
+	}
+	req.Header = p.header.Clone()
+	for _, cookie := range p.cookies {
+		req.AddCookie(cookie)
+	}
+	response, err := p.client.Do(req)
+	if err != nil {
+		return NewTTransportExceptionFromOsError(err)
+	}
+	if response.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, response.Body)
+		response.Body.Close()
+		return NewTTransportException(UNKNOWN_TRANSPORT_EXCEPTION, "HTTP Response code: "+strconv.Itoa(response.StatusCode))
+	}
+	p.response = response
+	return nil
+}
+
+// requestContext bounds the next request by the deadline, a later
+// SetDeadline in the past cancels it.
+func (p *THttpClient) requestContext() (context.Context, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.deadline.IsZero() && !p.deadline.After(time.Now()) {
+		return nil, NewTTransportException(TIMED_OUT, "Deadline exceeded before request was sent")
+	}
+	ctx := context.Background()
+	if p.deadline.IsZero() {
+		ctx, p.cancel = context.WithCancel(ctx)
+	} else {
+		ctx, p.cancel = context.WithDeadline(ctx, p.deadline)
+	}
+	return ctx, nil
+}
+
+func (p *THttpClient) Peek() bool {
+	return p.response != nil
+}
+
+/**
+ * Bounds the following requests by t. A time in the past aborts a request
+ * in flight, including the read of its response.
+ *
+ * @param t Deadline
+ */
+func (p *THttpClient) SetDeadline(t time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deadline = t
+	if !t.IsZero() && !t.After(time.Now()) && p.cancel != nil {
+		p.cancel()
+	}
+	return nil
+}
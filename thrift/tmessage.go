@@ -1,5 +1,7 @@
 package thrift
 
+import "strconv"
+
 // TMessage Helper class that encapsulates struct metadata.
 type TMessage interface {
 	Name() string
@@ -40,7 +42,7 @@ func (p *tMessage) SeqID() int {
 }
 
 func (p *tMessage) String() string {
-	return "<TMessage name:'" + p.name + "' type: " + string(p.typeID) + " seqid:" + string(p.seqid) + ">"
+	return "<TMessage name:'" + p.name + "' type: " + strconv.Itoa(int(p.typeID)) + " seqid:" + strconv.Itoa(int(p.seqid)) + ">"
 }
 
 func (p *tMessage) Equals(other TMessage) bool {
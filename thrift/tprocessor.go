@@ -0,0 +1,14 @@
+package thrift
+
+// TProcessor reads a call from in, invokes the handler and writes the reply
+// to out. It return false when the connection should not be used anymore,
+// even when the error is an application exception sent to the client.
+type TProcessor interface {
+	Process(in, out TProtocol) (bool, TException)
+}
+
+// TProcessorFunction processes one method once its message header has been
+// read by the TProcessor.
+type TProcessorFunction interface {
+	Process(seqId int32, in, out TProtocol) (bool, TException)
+}
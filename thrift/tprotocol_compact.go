@@ -0,0 +1,538 @@
+package thrift
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+)
+
+// compact protocol header constants
+const (
+	COMPACT_PROTOCOL_ID       = 0x82
+	COMPACT_VERSION           = 1
+	COMPACT_VERSION_MASK      = 0x1f
+	COMPACT_TYPE_MASK         = 0xe0
+	COMPACT_TYPE_SHIFT_AMOUNT = 5
+)
+
+// compact wire types, a field header or collection header carries these
+// instead of TType
+const (
+	compactBooleanTrue  = 0x01
+	compactBooleanFalse = 0x02
+	compactByte         = 0x03
+	compactI16          = 0x04
+	compactI32          = 0x05
+	compactI64          = 0x06
+	compactDouble       = 0x07
+	compactBinary       = 0x08
+	compactList         = 0x09
+	compactSet          = 0x0a
+	compactMap          = 0x0b
+	compactStruct       = 0x0c
+)
+
+var ttypeToCompactType = map[TType]byte{
+	STOP:   STOP,
+	BOOL:   compactBooleanTrue,
+	BYTE:   compactByte,
+	I16:    compactI16,
+	I32:    compactI32,
+	I64:    compactI64,
+	DOUBLE: compactDouble,
+	STRING: compactBinary,
+	LIST:   compactList,
+	SET:    compactSet,
+	MAP:    compactMap,
+	STRUCT: compactStruct,
+}
+
+// TCompactProtocol implements the thrift compact protocol: integers are
+// zigzag varints, field ids are sent as deltas and boolean fields are folded
+// into their field header.
+type TCompactProtocol struct {
+	trans TTransport
+
+	lastFieldID      int16
+	lastFieldIDStack []int16
+
+	// a bool field header is held back until its value is known
+	boolFieldName  string
+	boolFieldID    int16
+	boolFieldSet   bool
+	boolValue      bool
+	boolValueIsSet bool
+
+	buf [binary.MaxVarintLen64]byte
+}
+
+// NewTCompactProtocol NewTCompactProtocol
+func NewTCompactProtocol(t TTransport) *TCompactProtocol {
+	return &TCompactProtocol{trans: t}
+}
+
+// GetProtocol GetProtocol
+func (p *TCompactProtocol) GetProtocol(t TTransport) TProtocol {
+	return NewTCompactProtocol(t)
+}
+
+// // // // Write // // // //
+
+// WriteMessageBegin WriteMessageBegin
+func (p *TCompactProtocol) WriteMessageBegin(name string, typeID TMessageType, seqID int32) TProtocolException {
+	e := p.writeByteDirect(COMPACT_PROTOCOL_ID)
+	if e != nil {
+		return e
+	}
+	e = p.writeByteDirect(byte(COMPACT_VERSION&COMPACT_VERSION_MASK) | byte((int32(typeID)<<COMPACT_TYPE_SHIFT_AMOUNT)&COMPACT_TYPE_MASK))
+	if e != nil {
+		return e
+	}
+	e = p.writeVarint64(uint64(uint32(seqID)))
+	if e != nil {
+		return e
+	}
+	return p.WriteString(name)
+}
+
+func (p *TCompactProtocol) WriteMessageEnd() TProtocolException {
+	return nil
+}
+
+func (p *TCompactProtocol) WriteStructBegin(name string) TProtocolException {
+	p.lastFieldIDStack = append(p.lastFieldIDStack, p.lastFieldID)
+	p.lastFieldID = 0
+	return nil
+}
+
+func (p *TCompactProtocol) WriteStructEnd() TProtocolException {
+	n := len(p.lastFieldIDStack)
+	if n == 0 {
+		return NewTProtocolException(INVALID_DATA, "WriteStructEnd without WriteStructBegin")
+	}
+	p.lastFieldID = p.lastFieldIDStack[n-1]
+	p.lastFieldIDStack = p.lastFieldIDStack[:n-1]
+	return nil
+}
+
+func (p *TCompactProtocol) WriteFieldBegin(name string, typeID TType, id int16) TProtocolException {
+	if typeID == BOOL {
+		p.boolFieldName, p.boolFieldID, p.boolFieldSet = name, id, true
+		return nil
+	}
+	ctype, ok := ttypeToCompactType[typeID]
+	if !ok {
+		return NewTProtocolException(INVALID_DATA, "Unknown field type "+typeID.String())
+	}
+	return p.writeFieldHeader(ctype, id)
+}
+
+func (p *TCompactProtocol) writeFieldHeader(ctype byte, id int16) TProtocolException {
+	var e TProtocolException
+	if id > p.lastFieldID && id-p.lastFieldID <= 15 {
+		e = p.writeByteDirect(byte(id-p.lastFieldID)<<4 | ctype)
+	} else {
+		e = p.writeByteDirect(ctype)
+		if e == nil {
+			e = p.WriteI16(id)
+		}
+	}
+	p.lastFieldID = id
+	return e
+}
+
+func (p *TCompactProtocol) WriteFieldEnd() TProtocolException {
+	return nil
+}
+
+func (p *TCompactProtocol) WriteFieldStop() TProtocolException {
+	return p.writeByteDirect(STOP)
+}
+
+func (p *TCompactProtocol) WriteMapBegin(keyType TType, valueType TType, size int) TProtocolException {
+	if size == 0 {
+		return p.writeByteDirect(0)
+	}
+	e := p.writeVarint64(uint64(uint32(size)))
+	if e != nil {
+		return e
+	}
+	return p.writeByteDirect(ttypeToCompactType[keyType]<<4 | ttypeToCompactType[valueType])
+}
+
+func (p *TCompactProtocol) WriteMapEnd() TProtocolException {
+	return nil
+}
+
+func (p *TCompactProtocol) WriteListBegin(elemType TType, size int) TProtocolException {
+	return p.writeCollectionBegin(elemType, size)
+}
+
+func (p *TCompactProtocol) WriteListEnd() TProtocolException {
+	return nil
+}
+
+func (p *TCompactProtocol) WriteSetBegin(elemType TType, size int) TProtocolException {
+	return p.writeCollectionBegin(elemType, size)
+}
+
+func (p *TCompactProtocol) WriteSetEnd() TProtocolException {
+	return nil
+}
+
+func (p *TCompactProtocol) writeCollectionBegin(elemType TType, size int) TProtocolException {
+	ctype := ttypeToCompactType[elemType]
+	if size <= 14 {
+		return p.writeByteDirect(byte(size)<<4 | ctype)
+	}
+	e := p.writeByteDirect(0xf0 | ctype)
+	if e != nil {
+		return e
+	}
+	return p.writeVarint64(uint64(uint32(size)))
+}
+
+func (p *TCompactProtocol) WriteBool(value bool) TProtocolException {
+	ctype := byte(compactBooleanFalse)
+	if value {
+		ctype = compactBooleanTrue
+	}
+	if p.boolFieldSet {
+		p.boolFieldSet = false
+		return p.writeFieldHeader(ctype, p.boolFieldID)
+	}
+	return p.writeByteDirect(ctype)
+}
+
+func (p *TCompactProtocol) WriteByte(value int8) TProtocolException {
+	return p.writeByteDirect(byte(value))
+}
+
+func (p *TCompactProtocol) WriteI16(value int16) TProtocolException {
+	return p.writeVarint64(uint64(uint32(int32ToZigzag(int32(value)))))
+}
+
+func (p *TCompactProtocol) WriteI32(value int32) TProtocolException {
+	return p.writeVarint64(uint64(int32ToZigzag(value)))
+}
+
+func (p *TCompactProtocol) WriteI64(value int64) TProtocolException {
+	return p.writeVarint64(int64ToZigzag(value))
+}
+
+func (p *TCompactProtocol) WriteDouble(value float64) TProtocolException {
+	binary.LittleEndian.PutUint64(p.buf[:8], math.Float64bits(value))
+	_, e := p.trans.Write(p.buf[:8])
+	return NewTProtocolExceptionFromOsError(e)
+}
+
+func (p *TCompactProtocol) WriteString(value string) TProtocolException {
+	return p.WriteBinary([]byte(value))
+}
+
+func (p *TCompactProtocol) WriteBinary(value []byte) TProtocolException {
+	e := p.writeVarint64(uint64(uint32(len(value))))
+	if e != nil {
+		return e
+	}
+	if len(value) == 0 {
+		return nil
+	}
+	_, err := p.trans.Write(value)
+	return NewTProtocolExceptionFromOsError(err)
+}
+
+func (p *TCompactProtocol) writeByteDirect(b byte) TProtocolException {
+	p.buf[0] = b
+	_, e := p.trans.Write(p.buf[:1])
+	return NewTProtocolExceptionFromOsError(e)
+}
+
+func (p *TCompactProtocol) writeVarint64(n uint64) TProtocolException {
+	l := binary.PutUvarint(p.buf[:], n)
+	_, e := p.trans.Write(p.buf[:l])
+	return NewTProtocolExceptionFromOsError(e)
+}
+
+// // // // Read // // // //
+
+// ReadMessageBegin ReadMessageBegin
+func (p *TCompactProtocol) ReadMessageBegin() (name string, typeID TMessageType, seqID int32, err TProtocolException) {
+	protocolID, err := p.readByteDirect()
+	if err != nil {
+		return
+	}
+	if protocolID != COMPACT_PROTOCOL_ID {
+		return name, typeID, seqID, NewTProtocolException(BAD_VERSION, "Expected protocol id "+strconv.Itoa(COMPACT_PROTOCOL_ID)+" but got "+strconv.Itoa(int(protocolID)))
+	}
+	versionAndType, err := p.readByteDirect()
+	if err != nil {
+		return
+	}
+	if version := versionAndType & COMPACT_VERSION_MASK; version != COMPACT_VERSION {
+		return name, typeID, seqID, NewTProtocolException(BAD_VERSION, "Expected version "+strconv.Itoa(COMPACT_VERSION)+" but got "+strconv.Itoa(int(version)))
+	}
+	typeID = TMessageType((versionAndType >> COMPACT_TYPE_SHIFT_AMOUNT) & 0x07)
+	seq, err := p.readVarint64()
+	if err != nil {
+		return
+	}
+	seqID = int32(seq)
+	name, err = p.ReadString()
+	return name, typeID, seqID, err
+}
+
+func (p *TCompactProtocol) ReadMessageEnd() TProtocolException {
+	return nil
+}
+
+func (p *TCompactProtocol) ReadStructBegin() (name string, err TProtocolException) {
+	p.lastFieldIDStack = append(p.lastFieldIDStack, p.lastFieldID)
+	p.lastFieldID = 0
+	return
+}
+
+func (p *TCompactProtocol) ReadStructEnd() TProtocolException {
+	n := len(p.lastFieldIDStack)
+	if n == 0 {
+		return NewTProtocolException(INVALID_DATA, "ReadStructEnd without ReadStructBegin")
+	}
+	p.lastFieldID = p.lastFieldIDStack[n-1]
+	p.lastFieldIDStack = p.lastFieldIDStack[:n-1]
+	return nil
+}
+
+func (p *TCompactProtocol) ReadFieldBegin() (name string, typeID TType, id int16, err TProtocolException) {
+	b, err := p.readByteDirect()
+	if err != nil {
+		return
+	}
+	ctype := b & 0x0f
+	if ctype == STOP {
+		return name, STOP, 0, nil
+	}
+	if delta := int16(b >> 4); delta != 0 {
+		id = p.lastFieldID + delta
+	} else if id, err = p.ReadI16(); err != nil {
+		return
+	}
+	if typeID, err = compactTypeToTType(ctype); err != nil {
+		return
+	}
+	if ctype == compactBooleanTrue || ctype == compactBooleanFalse {
+		p.boolValue = ctype == compactBooleanTrue
+		p.boolValueIsSet = true
+	}
+	p.lastFieldID = id
+	return name, typeID, id, nil
+}
+
+func (p *TCompactProtocol) ReadFieldEnd() TProtocolException {
+	return nil
+}
+
+func (p *TCompactProtocol) ReadMapBegin() (kType, vType TType, size int, err TProtocolException) {
+	size32, err := p.readSize()
+	if err != nil || size32 == 0 {
+		return STOP, STOP, 0, err
+	}
+	kv, err := p.readByteDirect()
+	if err != nil {
+		return
+	}
+	if kType, err = compactTypeToTType(kv >> 4); err != nil {
+		return
+	}
+	if vType, err = compactTypeToTType(kv & 0x0f); err != nil {
+		return
+	}
+	return kType, vType, size32, nil
+}
+
+func (p *TCompactProtocol) ReadMapEnd() TProtocolException {
+	return nil
+}
+
+func (p *TCompactProtocol) ReadListBegin() (elemType TType, size int, err TProtocolException) {
+	return p.readCollectionBegin()
+}
+
+func (p *TCompactProtocol) ReadListEnd() TProtocolException {
+	return nil
+}
+
+func (p *TCompactProtocol) ReadSetBegin() (elemType TType, size int, err TProtocolException) {
+	return p.readCollectionBegin()
+}
+
+func (p *TCompactProtocol) ReadSetEnd() TProtocolException {
+	return nil
+}
+
+func (p *TCompactProtocol) readCollectionBegin() (elemType TType, size int, err TProtocolException) {
+	b, err := p.readByteDirect()
+	if err != nil {
+		return
+	}
+	size = int(b >> 4)
+	if size == 15 {
+		if size, err = p.readSize(); err != nil {
+			return
+		}
+	}
+	elemType, err = compactTypeToTType(b & 0x0f)
+	return elemType, size, err
+}
+
+func (p *TCompactProtocol) ReadBool() (value bool, err TProtocolException) {
+	if p.boolValueIsSet {
+		p.boolValueIsSet = false
+		return p.boolValue, nil
+	}
+	b, err := p.readByteDirect()
+	return b == compactBooleanTrue, err
+}
+
+func (p *TCompactProtocol) ReadByte() (value int8, err TProtocolException) {
+	b, err := p.readByteDirect()
+	return int8(b), err
+}
+
+func (p *TCompactProtocol) ReadI16() (value int16, err TProtocolException) {
+	v, err := p.readVarint64()
+	return int16(zigzagToInt32(uint32(v))), err
+}
+
+func (p *TCompactProtocol) ReadI32() (value int32, err TProtocolException) {
+	v, err := p.readVarint64()
+	return zigzagToInt32(uint32(v)), err
+}
+
+func (p *TCompactProtocol) ReadI64() (value int64, err TProtocolException) {
+	v, err := p.readVarint64()
+	return zigzagToInt64(v), err
+}
+
+func (p *TCompactProtocol) ReadDouble() (value float64, err TProtocolException) {
+	buf := p.buf[:8]
+	if _, e := p.trans.ReadAll(buf); e != nil {
+		return 0, NewTProtocolExceptionFromOsError(e)
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(buf)), nil
+}
+
+func (p *TCompactProtocol) ReadString() (value string, err TProtocolException) {
+	b, err := p.ReadBinary()
+	return string(b), err
+}
+
+func (p *TCompactProtocol) ReadBinary() ([]byte, TProtocolException) {
+	size, err := p.readSize()
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	if size == 0 {
+		return buf, nil
+	}
+	_, e := p.trans.ReadAll(buf)
+	return buf, NewTProtocolExceptionFromOsError(e)
+}
+
+func (p *TCompactProtocol) Flush() (err TProtocolException) {
+	return NewTProtocolExceptionFromOsError(p.trans.Flush())
+}
+
+func (p *TCompactProtocol) Skip(fieldType TType) (err TProtocolException) {
+	return SkipDefaultDepth(p, fieldType)
+}
+
+func (p *TCompactProtocol) Transport() TTransport {
+	return p.trans
+}
+
+func (p *TCompactProtocol) readByteDirect() (byte, TProtocolException) {
+	buf := p.buf[:1]
+	if _, e := p.trans.ReadAll(buf); e != nil {
+		return 0, NewTProtocolExceptionFromOsError(e)
+	}
+	return buf[0], nil
+}
+
+func (p *TCompactProtocol) readVarint64() (uint64, TProtocolException) {
+	var result uint64
+	var shift uint
+	for {
+		b, err := p.readByteDirect()
+		if err != nil {
+			return 0, err
+		}
+		if shift >= 64 {
+			return 0, NewTProtocolException(INVALID_DATA, "Variable-length int over 10 bytes")
+		}
+		result |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result, nil
+		}
+		shift += 7
+	}
+}
+
+// readSize reads a non negative varint32 length.
+func (p *TCompactProtocol) readSize() (int, TProtocolException) {
+	v, err := p.readVarint64()
+	if err != nil {
+		return 0, err
+	}
+	size := int32(v)
+	if size < 0 {
+		return 0, NewTProtocolException(NEGATIVE_SIZE, "Negative length: "+strconv.Itoa(int(size)))
+	}
+	return int(size), nil
+}
+
+func compactTypeToTType(ctype byte) (TType, TProtocolException) {
+	switch ctype {
+	case STOP:
+		return STOP, nil
+	case compactBooleanTrue, compactBooleanFalse:
+		return BOOL, nil
+	case compactByte:
+		return BYTE, nil
+	case compactI16:
+		return I16, nil
+	case compactI32:
+		return I32, nil
+	case compactI64:
+		return I64, nil
+	case compactDouble:
+		return DOUBLE, nil
+	case compactBinary:
+		return STRING, nil
+	case compactList:
+		return LIST, nil
+	case compactSet:
+		return SET, nil
+	case compactMap:
+		return MAP, nil
+	case compactStruct:
+		return STRUCT, nil
+	}
+	return STOP, NewTProtocolException(INVALID_DATA, "Unknown compact type "+strconv.Itoa(int(ctype)))
+}
+
+func int32ToZigzag(n int32) uint32 {
+	return uint32(n<<1) ^ uint32(n>>31)
+}
+
+func int64ToZigzag(n int64) uint64 {
+	return uint64(n<<1) ^ uint64(n>>63)
+}
+
+func zigzagToInt32(n uint32) int32 {
+	return int32(n>>1) ^ -int32(n&1)
+}
+
+func zigzagToInt64(n uint64) int64 {
+	return int64(n>>1) ^ -int64(n&1)
+}
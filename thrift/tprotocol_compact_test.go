@@ -0,0 +1,274 @@
+package thrift_test
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/J-J-J/hbase/Hbase"
+	"github.com/J-J-J/hbase/thrift"
+)
+
+// memoryTransport is a TTransport over a bytes.Buffer.
+type memoryTransport struct {
+	bytes.Buffer
+}
+
+func (t *memoryTransport) IsOpen() bool { return true }
+func (t *memoryTransport) Open() error  { return nil }
+func (t *memoryTransport) Close() error { return nil }
+func (t *memoryTransport) Flush() error { return nil }
+func (t *memoryTransport) Peek() bool   { return t.Len() > 0 }
+
+func (t *memoryTransport) ReadAll(buf []byte) (int, error) {
+	return thrift.ReadAllTransport(t, buf)
+}
+
+type tstruct interface {
+	Read(iprot thrift.TProtocol) thrift.TProtocolException
+	Write(oprot thrift.TProtocol) thrift.TProtocolException
+}
+
+// roundTrip writes in with the protocol of newProtocol, reads it back into
+// out and checks that every byte was read.
+func roundTrip(t *testing.T, newProtocol func(thrift.TTransport) thrift.TProtocol, in, out tstruct) {
+	t.Helper()
+	trans := &memoryTransport{}
+	if err := in.Write(newProtocol(trans)); err != nil {
+		t.Fatalf("write %T: %v", in, err)
+	}
+	if err := out.Read(newProtocol(trans)); err != nil {
+		t.Fatalf("read %T: %v", out, err)
+	}
+	if trans.Len() != 0 {
+		t.Fatalf("%T: %d bytes left unread", in, trans.Len())
+	}
+}
+
+func newCompact(trans thrift.TTransport) thrift.TProtocol {
+	return thrift.NewTCompactProtocol(trans)
+}
+
+func newBinary(trans thrift.TTransport) thrift.TProtocol {
+	return thrift.NewTBinaryProtocol(trans, false, true)
+}
+
+// checkRoundTrip round trips in with the compact and the binary protocols,
+// expecting want from both.
+func checkRoundTrip(t *testing.T, in tstruct, newOut func() tstruct, want interface{}) {
+	t.Helper()
+	compact, binary := newOut(), newOut()
+	roundTrip(t, newCompact, in, compact)
+	roundTrip(t, newBinary, in, binary)
+	if !reflect.DeepEqual(compact, want) {
+		t.Errorf("compact round trip of %T:\n got %+v\nwant %+v", in, compact, want)
+	}
+	if !reflect.DeepEqual(compact, binary) {
+		t.Errorf("compact and binary round trips of %T differ:\n%+v\n%+v", in, compact, binary)
+	}
+}
+
+func TestCompactTRowResult(t *testing.T) {
+	many := make(map[string]*Hbase.TCell)
+	for i := 0; i < 300; i++ {
+		many[fmt.Sprintf("cf:q%03d", i)] = &Hbase.TCell{Value: []byte{byte(i)}, Timestamp: int64(i) - 150}
+	}
+	tests := []*Hbase.TRowResult{
+		{Row: []byte("row1"), Columns: map[string]*Hbase.TCell{
+			"cf:a": {Value: []byte("v"), Timestamp: 1700000000000},
+			"cf:b": {Value: bytes.Repeat([]byte{0xff}, 70000), Timestamp: -1},
+			"cf:c": {Value: []byte{0}, Timestamp: math.MinInt64},
+			"cf:d": {Value: []byte("max"), Timestamp: math.MaxInt64},
+		}},
+		{Row: []byte("empty"), Columns: map[string]*Hbase.TCell{}},
+		{Row: []byte("many"), Columns: many},
+	}
+	for _, in := range tests {
+		checkRoundTrip(t, in, func() tstruct { return Hbase.NewTRowResult() }, in)
+	}
+}
+
+func TestCompactTScan(t *testing.T) {
+	columns := make([]Hbase.Text, 40)
+	for i := range columns {
+		columns[i] = []byte(fmt.Sprintf("cf:q%d", i))
+	}
+	tests := []*Hbase.TScan{
+		{},
+		{StartRow: []byte("a")},
+		{StopRow: []byte("z"), Caching: 100},
+		{
+			StartRow:     []byte("a"),
+			StopRow:      []byte("z"),
+			Timestamp:    -42,
+			Columns:      []Hbase.Text{[]byte("cf"), []byte("cf:q")},
+			Caching:      -1,
+			FilterString: []byte("KeyOnlyFilter()"),
+		},
+		{Timestamp: math.MaxInt64, Caching: math.MinInt32, Columns: columns},
+	}
+	for _, in := range tests {
+		checkRoundTrip(t, in, func() tstruct { return Hbase.NewTScan() }, in)
+	}
+}
+
+func TestCompactBools(t *testing.T) {
+	for _, in := range []*Hbase.Mutation{
+		{IsDelete: true, Column: []byte("cf:a"), WriteToWAL: false},
+		{IsDelete: false, Column: []byte("cf:a"), Value: []byte("v"), WriteToWAL: true},
+		{IsDelete: true, Column: []byte("cf:a"), WriteToWAL: true},
+	} {
+		checkRoundTrip(t, in, func() tstruct { return Hbase.NewMutation() }, in)
+	}
+	cd := &Hbase.ColumnDescriptor{
+		Name:                  []byte("cf:"),
+		MaxVersions:           3,
+		Compression:           "NONE",
+		InMemory:              true,
+		BloomFilterType:       "ROW",
+		BloomFilterVectorSize: -7,
+		BloomFilterNbHashes:   0,
+		BlockCacheEnabled:     false,
+		TimeToLive:            math.MaxInt32,
+	}
+	checkRoundTrip(t, cd, func() tstruct { return Hbase.NewColumnDescriptor() }, cd)
+}
+
+func TestCompactLists(t *testing.T) {
+	long := make([]*Hbase.Mutation, 1000)
+	for i := range long {
+		long[i] = &Hbase.Mutation{IsDelete: i%3 == 0, Column: []byte(fmt.Sprintf("cf:%d", i)), Value: []byte("v"), WriteToWAL: i%2 == 0}
+	}
+	for _, in := range []*Hbase.BatchMutation{
+		{Row: []byte("empty"), Mutations: []*Hbase.Mutation{}},
+		{Row: []byte("short"), Mutations: long[:14]},
+		{Row: []byte("fifteen"), Mutations: long[:15]},
+		{Row: []byte("long"), Mutations: long},
+	} {
+		checkRoundTrip(t, in, func() tstruct { return Hbase.NewBatchMutation() }, in)
+	}
+}
+
+// TestCompactFieldDeltas writes fields whose ids are more than 15 apart, which
+// the compact protocol encodes with a full header instead of a delta, and
+// negative ids.
+func TestCompactFieldDeltas(t *testing.T) {
+	type field struct {
+		id    int16
+		typ   thrift.TType
+		value interface{}
+	}
+	fields := []field{
+		{1, thrift.I32, int32(-1)},
+		{16, thrift.BOOL, true},
+		{17, thrift.BOOL, false},
+		{40, thrift.I64, int64(-1 << 40)},
+		{41, thrift.STRING, "delta one"},
+		{300, thrift.I16, int16(-300)},
+		{32767, thrift.DOUBLE, -0.5},
+		{-1, thrift.I32, int32(math.MinInt32)},
+		{2, thrift.BOOL, true},
+	}
+	for _, newProtocol := range []func(thrift.TTransport) thrift.TProtocol{newCompact, newBinary} {
+		trans := &memoryTransport{}
+		oprot := newProtocol(trans)
+		oprot.WriteStructBegin("test")
+		for _, f := range fields {
+			oprot.WriteFieldBegin("", f.typ, f.id)
+			switch v := f.value.(type) {
+			case bool:
+				oprot.WriteBool(v)
+			case int16:
+				oprot.WriteI16(v)
+			case int32:
+				oprot.WriteI32(v)
+			case int64:
+				oprot.WriteI64(v)
+			case float64:
+				oprot.WriteDouble(v)
+			case string:
+				oprot.WriteString(v)
+			}
+			oprot.WriteFieldEnd()
+		}
+		oprot.WriteFieldStop()
+		oprot.WriteStructEnd()
+
+		iprot := newProtocol(trans)
+		iprot.ReadStructBegin()
+		for _, f := range fields {
+			_, typ, id, err := iprot.ReadFieldBegin()
+			if err != nil || typ != f.typ || id != f.id {
+				t.Fatalf("%T: field %d %v: got %d %v, %v", iprot, f.id, f.typ, id, typ, err)
+			}
+			var got interface{}
+			switch f.value.(type) {
+			case bool:
+				got, err = iprot.ReadBool()
+			case int16:
+				got, err = iprot.ReadI16()
+			case int32:
+				got, err = iprot.ReadI32()
+			case int64:
+				got, err = iprot.ReadI64()
+			case float64:
+				got, err = iprot.ReadDouble()
+			case string:
+				got, err = iprot.ReadString()
+			}
+			if err != nil || got != f.value {
+				t.Fatalf("%T: field %d: got %v, want %v, %v", iprot, f.id, got, f.value, err)
+			}
+			iprot.ReadFieldEnd()
+		}
+		if _, typ, _, err := iprot.ReadFieldBegin(); err != nil || typ != thrift.STOP {
+			t.Fatalf("%T: got %v, want STOP, %v", iprot, typ, err)
+		}
+		iprot.ReadStructEnd()
+		if trans.Len() != 0 {
+			t.Fatalf("%T: %d bytes left unread", iprot, trans.Len())
+		}
+	}
+}
+
+// TestCompactSkip reads a TScan written with an unknown field of every kind,
+// which the generated code skips.
+func TestCompactSkip(t *testing.T) {
+	trans := &memoryTransport{}
+	oprot := thrift.NewTCompactProtocol(trans)
+	oprot.WriteStructBegin("TScan")
+	oprot.WriteFieldBegin("startRow", thrift.STRING, 1)
+	oprot.WriteBinary([]byte("a"))
+	oprot.WriteFieldEnd()
+	oprot.WriteFieldBegin("", thrift.MAP, 20)
+	oprot.WriteMapBegin(thrift.STRING, thrift.LIST, 1)
+	oprot.WriteString("k")
+	oprot.WriteListBegin(thrift.BOOL, 2)
+	oprot.WriteBool(true)
+	oprot.WriteBool(false)
+	oprot.WriteListEnd()
+	oprot.WriteMapEnd()
+	oprot.WriteFieldEnd()
+	oprot.WriteFieldBegin("", thrift.STRUCT, 21)
+	(&Hbase.TCell{Value: []byte("x"), Timestamp: -5}).Write(oprot)
+	oprot.WriteFieldEnd()
+	oprot.WriteFieldBegin("", thrift.BOOL, 22)
+	oprot.WriteBool(true)
+	oprot.WriteFieldEnd()
+	oprot.WriteFieldBegin("caching", thrift.I32, 5)
+	oprot.WriteI32(-9)
+	oprot.WriteFieldEnd()
+	oprot.WriteFieldStop()
+	oprot.WriteStructEnd()
+
+	scan := Hbase.NewTScan()
+	if err := scan.Read(thrift.NewTCompactProtocol(trans)); err != nil {
+		t.Fatal(err)
+	}
+	want := &Hbase.TScan{StartRow: []byte("a"), Caching: -9}
+	if !reflect.DeepEqual(scan, want) || trans.Len() != 0 {
+		t.Fatalf("got %+v, want %+v, %d bytes left", scan, want, trans.Len())
+	}
+}
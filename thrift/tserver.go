@@ -0,0 +1,138 @@
+package thrift
+
+import (
+	"net"
+	"sync"
+)
+
+// TServer serves a TProcessor until stopped.
+type TServer interface {
+	Serve() error
+	Stop() error
+}
+
+// TSimpleServer serves every accepted connection in its own goroutine, like
+// the thread per connection server of the java library. Calls on one
+// connection are processed in order.
+type TSimpleServer struct {
+	processor        TProcessor
+	listener         net.Listener
+	transportFactory TTransportFactory
+	protocolFactory  TProtocolFactory
+
+	mu      sync.Mutex
+	conns   map[net.Conn]struct{}
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewTSimpleServer return a server speaking the binary protocol over plain
+// sockets accepted from listener.
+func NewTSimpleServer(processor TProcessor, listener net.Listener) *TSimpleServer {
+	return NewTSimpleServerFactory(processor, listener, NewTTransportFactory(), NewTBinaryProtocol(nil, false, true))
+}
+
+/**
+ * Creates a server for processor on listener.
+ *
+ * @param processor        Processor handling the calls
+ * @param listener         Listener the connections are accepted from
+ * @param transportFactory Wraps every accepted socket, for example with
+ *                         NewTFramedTransportFactory
+ * @param protocolFactory  Protocol spoken on every connection
+ */
+func NewTSimpleServerFactory(processor TProcessor, listener net.Listener, transportFactory TTransportFactory, protocolFactory TProtocolFactory) *TSimpleServer {
+	return &TSimpleServer{
+		processor:        processor,
+		listener:         listener,
+		transportFactory: transportFactory,
+		protocolFactory:  protocolFactory,
+		conns:            make(map[net.Conn]struct{}),
+	}
+}
+
+// Addr return the address the server listens on.
+func (p *TSimpleServer) Addr() net.Addr {
+	return p.listener.Addr()
+}
+
+// Serve accepts connections until Stop is called, it then return nil.
+func (p *TSimpleServer) Serve() error {
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			if p.isStopped() {
+				return nil
+			}
+			return NewTTransportExceptionFromOsError(err)
+		}
+		if !p.track(conn) {
+			conn.Close()
+			return nil
+		}
+		go p.serve(conn)
+	}
+}
+
+// Stop closes the listener and every connection, then waits for the calls
+// in progress to return.
+func (p *TSimpleServer) Stop() error {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return nil
+	}
+	p.stopped = true
+	err := p.listener.Close()
+	for conn := range p.conns {
+		conn.Close()
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+	return err
+}
+
+func (p *TSimpleServer) isStopped() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stopped
+}
+
+func (p *TSimpleServer) track(conn net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return false
+	}
+	p.conns[conn] = struct{}{}
+	p.wg.Add(1)
+	return true
+}
+
+func (p *TSimpleServer) untrack(conn net.Conn) {
+	p.mu.Lock()
+	delete(p.conns, conn)
+	p.mu.Unlock()
+	p.wg.Done()
+}
+
+// serve processes the calls of one connection until the processor return
+// false or fails. An application exception has already been sent back to
+// the client and does not end the connection by itself.
+func (p *TSimpleServer) serve(conn net.Conn) {
+	defer p.untrack(conn)
+	defer conn.Close()
+	sock, _ := NewTSocketConn(conn)
+	trans := p.transportFactory.GetTransport(sock)
+	in := p.protocolFactory.GetProtocol(trans)
+	out := p.protocolFactory.GetProtocol(trans)
+	for {
+		ok, err := p.processor.Process(in, out)
+		if !ok {
+			return
+		}
+		if _, isApp := err.(TApplicationException); err != nil && !isApp {
+			return
+		}
+	}
+}
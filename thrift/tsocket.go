@@ -2,7 +2,9 @@ package thrift
 
 import (
 	"bytes"
+	"crypto/tls"
 	"net"
+	"sync"
 	"time"
 )
 
@@ -12,6 +14,9 @@ type TSocket struct {
 	conn        net.Conn
 	addr        net.Addr
 	nsecTimeout int64
+	tlsConfig   *tls.Config // dial with TLS when set
+	mu          sync.Mutex  // guards conn deadlines against SetDeadline
+	deadline    time.Time
 }
 
 // NewTSocketConn Constructor that takes an already created socket.
@@ -58,6 +63,24 @@ func NewTSocket(address net.Addr, nsecTimeout int64) *TSocket {
 	return sock
 }
 
+/**
+ * Creates a new unconnected socket that will connect to the given host
+ * on the given port over TLS.
+ *
+ * @param address Remote address
+ * @param config  TLS configuration, its ServerName is used for SNI and
+ *                certificate verification
+ * @param nsecTimeout Socket timeout
+ */
+func NewTSSLSocket(address net.Addr, config *tls.Config, nsecTimeout int64) *TSocket {
+	sock := NewTSocket(address, nsecTimeout)
+	if config == nil {
+		config = &tls.Config{}
+	}
+	sock.tlsConfig = config
+	return sock
+}
+
 /**
  * Sets the socket timeout
  *
@@ -68,11 +91,38 @@ func (p *TSocket) SetTimeout(nsecTimeout int64) error {
 	return nil
 }
 
-func (p *TSocket) pushDeadline(read, write bool) {
+/**
+ * Sets an absolute deadline on top of the socket timeout, the earlier of
+ * the two applies. A zero time removes it, a time in the past interrupts
+ * a pending read or write.
+ *
+ * @param t Deadline
+ */
+func (p *TSocket) SetDeadline(t time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deadline = t
+	if p.conn == nil {
+		return nil
+	}
+	return p.conn.SetDeadline(p.nextDeadline())
+}
+
+func (p *TSocket) nextDeadline() time.Time {
 	var t time.Time
 	if p.nsecTimeout > 0 {
 		t = time.Now().Add(time.Duration(p.nsecTimeout))
 	}
+	if !p.deadline.IsZero() && (t.IsZero() || p.deadline.Before(t)) {
+		t = p.deadline
+	}
+	return t
+}
+
+func (p *TSocket) pushDeadline(read, write bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	t := p.nextDeadline()
 	if read && write {
 		p.conn.SetDeadline(t)
 	} else if read {
@@ -115,16 +165,20 @@ func (p *TSocket) Open() error {
 	if len(p.addr.String()) == 0 {
 		return NewTTransportException(NOT_OPEN, "Cannot open bad address.")
 	}
+	dialer := &net.Dialer{Timeout: time.Duration(p.nsecTimeout)}
+	var conn net.Conn
 	var err error
-	if p.nsecTimeout > 0 {
-		if p.conn, err = net.DialTimeout(p.addr.Network(), p.addr.String(), time.Duration(p.nsecTimeout)); err != nil {
-			return NewTTransportException(NOT_OPEN, err.Error())
-		}
+	if p.tlsConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, p.addr.Network(), p.addr.String(), p.tlsConfig)
 	} else {
-		if p.conn, err = net.Dial(p.addr.Network(), p.addr.String()); err != nil {
-			return NewTTransportException(NOT_OPEN, err.Error())
-		}
+		conn, err = dialer.Dial(p.addr.Network(), p.addr.String())
+	}
+	if err != nil {
+		return NewTTransportException(NOT_OPEN, err.Error())
 	}
+	p.mu.Lock()
+	p.conn = conn
+	p.mu.Unlock()
 	return nil
 }
 
@@ -133,6 +187,8 @@ func (p *TSocket) Open() error {
  */
 func (p *TSocket) Close() error {
 	// Close the socket
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	if p.conn != nil {
 		err := p.conn.Close()
 		if err != nil {
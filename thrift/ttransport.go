@@ -2,6 +2,7 @@ package thrift
 
 import (
 	"strconv"
+	"time"
 )
 
 // TTransport Generic class that encapsulates the I/O layer. This is basically a thin
@@ -72,6 +73,18 @@ type TTransport interface {
 	Peek() bool
 }
 
+// TDeadlineTransport is implemented by transports whose blocking I/O can be
+// bounded by an absolute point in time.
+type TDeadlineTransport interface {
+	TTransport
+
+	/**
+	 * Bounds all following reads, writes and flushes by t. A zero t removes
+	 * the deadline, a t in the past interrupts any pending I/O.
+	 */
+	SetDeadline(t time.Time) error
+}
+
 // ReadAllTransport Guarantees that all of len bytes are actually read off the transport.
 // @param buf Array to read into
 // @param off Index to start reading at
@@ -92,3 +105,20 @@ func ReadAllTransport(p TTransport, buf []byte) (n int, err error) {
 	}
 	return n, err
 }
+
+// TTransportFactory wraps the transport of every accepted connection, for
+// example to add framing or buffering on a server.
+type TTransportFactory interface {
+	GetTransport(trans TTransport) TTransport
+}
+
+type tTransportFactory struct{}
+
+// NewTTransportFactory return a factory using the transports unchanged.
+func NewTTransportFactory() TTransportFactory {
+	return &tTransportFactory{}
+}
+
+func (p *tTransportFactory) GetTransport(trans TTransport) TTransport {
+	return trans
+}